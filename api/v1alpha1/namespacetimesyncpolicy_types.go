@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceTimeSyncPolicySpec defines the sidecar settings a tenant wants for
+// their namespace. Each field only takes effect if the TimeSyncPolicy that
+// selects the namespace lists it in allowedOverrides.
+type NamespaceTimeSyncPolicySpec struct {
+	// Enable set to false opts the namespace out of injection.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// Image replaces the sidecar image of the cluster policy.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// InjectionTarget replaces the injection target of the cluster policy.
+	// +optional
+	InjectionTarget InjectionTarget `json:"injectionTarget,omitempty"`
+}
+
+// NamespaceTimeSyncPolicyStatus defines the observed state of NamespaceTimeSyncPolicy.
+type NamespaceTimeSyncPolicyStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced
+
+// NamespaceTimeSyncPolicy is the Schema for the namespacetimesyncpolicies API.
+type NamespaceTimeSyncPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceTimeSyncPolicySpec   `json:"spec,omitempty"`
+	Status NamespaceTimeSyncPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceTimeSyncPolicyList contains a list of NamespaceTimeSyncPolicy.
+type NamespaceTimeSyncPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceTimeSyncPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceTimeSyncPolicy{}, &NamespaceTimeSyncPolicyList{})
+}
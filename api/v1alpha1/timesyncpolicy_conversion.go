@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// PreservedFieldsAnnotation holds the v1beta1 spec and status of an object
+// read as v1alpha1, so that fields v1alpha1 cannot express survive a round
+// trip through it.
+const PreservedFieldsAnnotation = "sync.example.com/v1beta1-preserved"
+
+type preservedFields struct {
+	Spec   v1beta1.TimeSyncPolicySpec   `json:"spec"`
+	Status v1beta1.TimeSyncPolicyStatus `json:"status"`
+}
+
+// ConvertTo converts this TimeSyncPolicy to the Hub version (v1beta1).
+func (src *TimeSyncPolicy) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.TimeSyncPolicy)
+	if !ok {
+		return fmt.Errorf("expected a *v1beta1.TimeSyncPolicy but got %T", dstRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = v1beta1.TimeSyncPolicySpec{}
+	dst.Status = v1beta1.TimeSyncPolicyStatus{}
+
+	if raw, ok := dst.Annotations[PreservedFieldsAnnotation]; ok {
+		var preserved preservedFields
+		if err := json.Unmarshal([]byte(raw), &preserved); err != nil {
+			return fmt.Errorf("decoding %s: %w", PreservedFieldsAnnotation, err)
+		}
+		dst.Spec, dst.Status = preserved.Spec, preserved.Status
+		delete(dst.Annotations, PreservedFieldsAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	} else {
+		setHubDefaults(dst)
+	}
+
+	convertToHub(src, dst)
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *TimeSyncPolicy) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.TimeSyncPolicy)
+	if !ok {
+		return fmt.Errorf("expected a *v1beta1.TimeSyncPolicy but got %T", srcRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = TimeSyncPolicySpec{
+		NamespaceSelector: *src.Spec.NamespaceSelector.DeepCopy(),
+		Enable:            src.Spec.Enable,
+		Image:             src.Spec.Template.Image,
+		PodSelector:       src.Spec.PodSelector.DeepCopy(),
+		ExcludeNamespaces: append([]string(nil), src.Spec.ExcludeNamespaces...),
+		ExcludePods:       append([]string(nil), src.Spec.ExcludePods...),
+		InjectionTarget:   InjectionTarget(src.Spec.InjectionTarget),
+	}
+	for _, f := range src.Spec.AllowedOverrides {
+		dst.Spec.AllowedOverrides = append(dst.Spec.AllowedOverrides, OverridableField(f))
+	}
+	dst.Status = TimeSyncPolicyStatus{
+		MatchedNamespaces: src.Status.MatchedNamespaces,
+		MatchedPods:       src.Status.SelectedPods,
+	}
+
+	// Only keep the annotation when v1alpha1 cannot represent the object.
+	var restored v1beta1.TimeSyncPolicy
+	setHubDefaults(&restored)
+	convertToHub(dst, &restored)
+	if equality.Semantic.DeepEqual(restored.Spec, src.Spec) && equality.Semantic.DeepEqual(restored.Status, src.Status) {
+		return nil
+	}
+
+	raw, err := json.Marshal(preservedFields{Spec: src.Spec, Status: src.Status})
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", PreservedFieldsAnnotation, err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[PreservedFieldsAnnotation] = string(raw)
+	return nil
+}
+
+// setHubDefaults sets the defaults of the v1beta1-only fields.
+func setHubDefaults(dst *v1beta1.TimeSyncPolicy) {
+	dst.Spec.Mode = v1beta1.PolicyModeEnforce
+	dst.Spec.Enforcement = v1beta1.EnforcementOptional
+	dst.Spec.Backend = v1beta1.BackendGeneric
+	dst.Spec.ClockAdjustment = v1beta1.ClockAdjustmentNone
+	dst.Spec.PodSecurityAction = v1beta1.PodSecurityActionDowngrade
+	dst.Spec.QuotaAction = v1beta1.QuotaActionWarn
+	dst.Spec.Skip = []v1beta1.SkipReason{
+		v1beta1.SkipReasonWindows,
+		v1beta1.SkipReasonHostNetwork,
+		v1beta1.SkipReasonMirrorPod,
+		v1beta1.SkipReasonDaemonSet,
+		v1beta1.SkipReasonNodeDaemon,
+	}
+	dst.Spec.NodeDaemonLabel = "sync.example.com/node-daemon"
+	dst.Spec.RevisionHistoryLimit = ptr.To[int32](10)
+}
+
+// convertToHub copies the fields v1alpha1 knows about onto dst, leaving the
+// v1beta1-only fields untouched.
+func convertToHub(src *TimeSyncPolicy, dst *v1beta1.TimeSyncPolicy) {
+	dst.Spec.NamespaceSelector = *src.Spec.NamespaceSelector.DeepCopy()
+	dst.Spec.Enable = src.Spec.Enable
+	dst.Spec.Template.Image = src.Spec.Image
+	dst.Spec.PodSelector = src.Spec.PodSelector.DeepCopy()
+	dst.Spec.ExcludeNamespaces = append([]string(nil), src.Spec.ExcludeNamespaces...)
+	dst.Spec.ExcludePods = append([]string(nil), src.Spec.ExcludePods...)
+	dst.Spec.InjectionTarget = v1beta1.InjectionTarget(src.Spec.InjectionTarget)
+	dst.Spec.AllowedOverrides = nil
+	for _, f := range src.Spec.AllowedOverrides {
+		dst.Spec.AllowedOverrides = append(dst.Spec.AllowedOverrides, v1beta1.OverridableField(f))
+	}
+	dst.Status.MatchedNamespaces = src.Status.MatchedNamespaces
+	dst.Status.SelectedPods = src.Status.MatchedPods
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"math/rand"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/utils/ptr"
+
+	"github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// newFuzzer returns a fuzzer that only produces values the API server could
+// round trip through JSON.
+func newFuzzer(t *testing.T, seed int64) *fuzz.Fuzzer {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	funcs := fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, func(serializer.CodecFactory) []interface{} {
+		return []interface{}{
+			// Timestamps are serialized with second precision.
+			func(j *metav1.Time, c fuzz.Continue) {
+				*j = metav1.Unix(c.Int63n(1<<32), 0).Rfc3339Copy()
+			},
+		}
+	})
+	return fuzzer.FuzzerFor(funcs, rand.NewSource(seed), serializer.NewCodecFactory(scheme))
+}
+
+func FuzzAlphaRoundTrip(f *testing.F) {
+	for seed := int64(0); seed < 20; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		var src TimeSyncPolicy
+		newFuzzer(t, seed).Fuzz(&src)
+		delete(src.Annotations, PreservedFieldsAnnotation)
+
+		var hub v1beta1.TimeSyncPolicy
+		if err := src.ConvertTo(&hub); err != nil {
+			t.Fatalf("ConvertTo: %v", err)
+		}
+		var dst TimeSyncPolicy
+		if err := dst.ConvertFrom(&hub); err != nil {
+			t.Fatalf("ConvertFrom: %v", err)
+		}
+		dst.TypeMeta = src.TypeMeta
+		if !equality.Semantic.DeepEqual(&src, &dst) {
+			t.Errorf("round trip changed the object:\nbefore: %+v\nafter:  %+v", src, dst)
+		}
+	})
+}
+
+func FuzzHubRoundTrip(f *testing.F) {
+	for seed := int64(0); seed < 20; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		var src v1beta1.TimeSyncPolicy
+		newFuzzer(t, seed).Fuzz(&src)
+		delete(src.Annotations, PreservedFieldsAnnotation)
+
+		var spoke TimeSyncPolicy
+		if err := spoke.ConvertFrom(&src); err != nil {
+			t.Fatalf("ConvertFrom: %v", err)
+		}
+		var dst v1beta1.TimeSyncPolicy
+		if err := spoke.ConvertTo(&dst); err != nil {
+			t.Fatalf("ConvertTo: %v", err)
+		}
+		dst.TypeMeta = src.TypeMeta
+		if !equality.Semantic.DeepEqual(&src, &dst) {
+			t.Errorf("round trip changed the object:\nbefore: %+v\nafter:  %+v", src, dst)
+		}
+	})
+}
+
+func TestConvertToSetsDefaults(t *testing.T) {
+	src := &TimeSyncPolicy{Spec: TimeSyncPolicySpec{Enable: true, Image: "timesync:latest"}}
+	var dst v1beta1.TimeSyncPolicy
+	if err := src.ConvertTo(&dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Spec.Mode != v1beta1.PolicyModeEnforce {
+		t.Errorf("Mode = %q, want %q", dst.Spec.Mode, v1beta1.PolicyModeEnforce)
+	}
+	if dst.Spec.Enforcement != v1beta1.EnforcementOptional {
+		t.Errorf("Enforcement = %q, want %q", dst.Spec.Enforcement, v1beta1.EnforcementOptional)
+	}
+	if dst.Spec.Backend != v1beta1.BackendGeneric {
+		t.Errorf("Backend = %q, want %q", dst.Spec.Backend, v1beta1.BackendGeneric)
+	}
+	if dst.Spec.ClockAdjustment != v1beta1.ClockAdjustmentNone {
+		t.Errorf("ClockAdjustment = %q, want %q", dst.Spec.ClockAdjustment, v1beta1.ClockAdjustmentNone)
+	}
+	if dst.Spec.PodSecurityAction != v1beta1.PodSecurityActionDowngrade {
+		t.Errorf("PodSecurityAction = %q, want %q", dst.Spec.PodSecurityAction, v1beta1.PodSecurityActionDowngrade)
+	}
+	if dst.Spec.QuotaAction != v1beta1.QuotaActionWarn {
+		t.Errorf("QuotaAction = %q, want %q", dst.Spec.QuotaAction, v1beta1.QuotaActionWarn)
+	}
+	if len(dst.Spec.Skip) != 5 {
+		t.Errorf("Skip = %v, want every skip reason", dst.Spec.Skip)
+	}
+	if dst.Spec.NodeDaemonLabel != "sync.example.com/node-daemon" {
+		t.Errorf("NodeDaemonLabel = %q, want %q", dst.Spec.NodeDaemonLabel, "sync.example.com/node-daemon")
+	}
+	if ptr.Deref(dst.Spec.RevisionHistoryLimit, 0) != 10 {
+		t.Errorf("RevisionHistoryLimit = %v, want 10", dst.Spec.RevisionHistoryLimit)
+	}
+	if dst.Spec.Template.Image != "timesync:latest" {
+		t.Errorf("Template.Image = %q, want %q", dst.Spec.Template.Image, "timesync:latest")
+	}
+}
+
+func TestConvertFromOnlyAnnotatesLossyObjects(t *testing.T) {
+	src := &v1beta1.TimeSyncPolicy{Spec: v1beta1.TimeSyncPolicySpec{
+		Enable:            true,
+		Mode:              v1beta1.PolicyModeEnforce,
+		Enforcement:       v1beta1.EnforcementOptional,
+		Template:          v1beta1.SidecarTemplate{Image: "timesync:latest"},
+		Backend:           v1beta1.BackendGeneric,
+		ClockAdjustment:   v1beta1.ClockAdjustmentNone,
+		PodSecurityAction: v1beta1.PodSecurityActionDowngrade,
+		QuotaAction:       v1beta1.QuotaActionWarn,
+		Skip: []v1beta1.SkipReason{
+			v1beta1.SkipReasonWindows,
+			v1beta1.SkipReasonHostNetwork,
+			v1beta1.SkipReasonMirrorPod,
+			v1beta1.SkipReasonDaemonSet,
+			v1beta1.SkipReasonNodeDaemon,
+		},
+		NodeDaemonLabel:      "sync.example.com/node-daemon",
+		RevisionHistoryLimit: ptr.To[int32](10),
+	}}
+	var dst TimeSyncPolicy
+	if err := dst.ConvertFrom(src); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dst.Annotations[PreservedFieldsAnnotation]; ok {
+		t.Errorf("lossless conversion added %s", PreservedFieldsAnnotation)
+	}
+
+	src.Spec.Backend = v1beta1.BackendChrony
+	if err := dst.ConvertFrom(src); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dst.Annotations[PreservedFieldsAnnotation]; !ok {
+		t.Errorf("lossy conversion did not add %s", PreservedFieldsAnnotation)
+	}
+}
@@ -23,11 +23,69 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// InjectionTarget selects which objects the webhook adds the sidecar to.
+// +kubebuilder:validation:Enum=Pods;Workloads;PodsAndWorkloads
+type InjectionTarget string
+
+const (
+	// InjectionTargetPods injects the sidecar into Pods at creation.
+	InjectionTargetPods InjectionTarget = "Pods"
+	// InjectionTargetWorkloads injects the sidecar into the pod templates of
+	// Deployments, StatefulSets, DaemonSets, Jobs and CronJobs.
+	InjectionTargetWorkloads InjectionTarget = "Workloads"
+	// InjectionTargetPodsAndWorkloads does both.
+	InjectionTargetPodsAndWorkloads InjectionTarget = "PodsAndWorkloads"
+)
+
+// OverridableField names a TimeSyncPolicy setting that tenants may override
+// with a NamespaceTimeSyncPolicy.
+// +kubebuilder:validation:Enum=Enable;Image;InjectionTarget
+type OverridableField string
+
+const (
+	// OverridableFieldEnable lets tenants opt their namespace out.
+	OverridableFieldEnable OverridableField = "Enable"
+	// OverridableFieldImage lets tenants choose the sidecar image.
+	OverridableFieldImage OverridableField = "Image"
+	// OverridableFieldInjectionTarget lets tenants choose the injection target.
+	OverridableFieldInjectionTarget OverridableField = "InjectionTarget"
+)
+
 // TimeSyncPolicySpec defines the desired state of TimeSyncPolicy.
 type TimeSyncPolicySpec struct {
 	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
 	Enable            bool                 `json:"enable"`
 	Image             string               `json:"image"`
+
+	// PodSelector further restricts injection to pods with matching labels.
+	// When unset, every pod in a selected namespace matches.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// ExcludeNamespaces lists namespaces that never match, even if selected
+	// by NamespaceSelector.
+	// +listType=set
+	// +optional
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// ExcludePods lists pods that never match, as "name" or "namespace/name".
+	// A trailing "*" matches a name prefix, which also covers pods created
+	// from a generateName.
+	// +listType=set
+	// +optional
+	ExcludePods []string `json:"excludePods,omitempty"`
+
+	// InjectionTarget selects whether the sidecar is added to Pods, to
+	// workload pod templates so it shows up in the workload spec, or to both.
+	// +kubebuilder:default=Pods
+	// +optional
+	InjectionTarget InjectionTarget `json:"injectionTarget,omitempty"`
+
+	// AllowedOverrides lists the fields that a NamespaceTimeSyncPolicy in a
+	// matched namespace may override. Fields not listed are locked.
+	// +listType=set
+	// +optional
+	AllowedOverrides []OverridableField `json:"allowedOverrides,omitempty"`
 }
 
 // TimeSyncPolicyStatus defines the observed state of TimeSyncPolicy.
@@ -35,11 +93,17 @@ type TimeSyncPolicyStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	MatchedNamespaces int `json:"matchedNamespaces"`
+
+	// MatchedPods is the number of existing pods in matched namespaces that
+	// are selected by the policy.
+	// +optional
+	MatchedPods int `json:"matchedPods,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:deprecatedversion:warning="sync.example.com/v1alpha1 TimeSyncPolicy is deprecated; use sync.example.com/v1beta1"
 
 // TimeSyncPolicy is the Schema for the timesyncpolicies API.
 type TimeSyncPolicy struct {
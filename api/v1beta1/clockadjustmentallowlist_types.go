@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClockAdjustmentAllowlistSpec lists the namespaces whose timesync sidecars
+// may adjust the node clock, which requires CAP_SYS_TIME.
+type ClockAdjustmentAllowlistSpec struct {
+	// Namespaces lists namespaces by name.
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector selects namespaces by label. When unset, only the
+	// namespaces listed by name are allowed.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// ClockAdjustmentAllowlistStatus defines the observed state of ClockAdjustmentAllowlist.
+type ClockAdjustmentAllowlistStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// ClockAdjustmentAllowlist is the Schema for the clockadjustmentallowlists API.
+// A namespace may run sidecars with a clockAdjustment other than None only if
+// at least one allowlist permits it.
+type ClockAdjustmentAllowlist struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClockAdjustmentAllowlistSpec   `json:"spec,omitempty"`
+	Status ClockAdjustmentAllowlistStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClockAdjustmentAllowlistList contains a list of ClockAdjustmentAllowlist.
+type ClockAdjustmentAllowlistList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClockAdjustmentAllowlist `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClockAdjustmentAllowlist{}, &ClockAdjustmentAllowlistList{})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TimeSyncExceptionSpec exempts pods from sidecar injection until it expires.
+// +kubebuilder:validation:XValidation:rule="has(self.namespaces) || has(self.namespaceSelector) || has(self.podSelector)",message="an exception must target namespaces or pods"
+type TimeSyncExceptionSpec struct {
+	// Namespaces lists exempted namespaces by name.
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector selects exempted namespaces by label, in addition to
+	// those listed by name. Pods in every namespace are exempted when neither
+	// is set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// PodSelector restricts the exception to pods with matching labels. When
+	// unset, every pod of the exempted namespaces is exempted.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Policies restricts the exception to the named TimeSyncPolicies. When
+	// unset, the pods are exempted from every policy.
+	// +listType=set
+	// +optional
+	Policies []string `json:"policies,omitempty"`
+
+	// Reason explains why the pods are exempted.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+
+	// Owner is who is responsible for the exception, such as a team or an
+	// email address.
+	// +kubebuilder:validation:MinLength=1
+	Owner string `json:"owner"`
+
+	// ExpiresAt is when the exception stops applying.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// Condition types reported on TimeSyncException.
+const (
+	// ConditionActive is True while the exception applies.
+	ConditionActive = "Active"
+)
+
+// TimeSyncExceptionStatus defines the observed state of TimeSyncException.
+type TimeSyncExceptionStatus struct {
+	// Conditions describe the current state of the exception.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.owner`
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Active",type=string,JSONPath=`.status.conditions[?(@.type=="Active")].status`
+
+// TimeSyncException is the Schema for the timesyncexceptions API. It keeps
+// the webhook from injecting the sidecar into the pods it targets until it
+// expires, without touching the labels other policies select on.
+type TimeSyncException struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TimeSyncExceptionSpec   `json:"spec,omitempty"`
+	Status TimeSyncExceptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TimeSyncExceptionList contains a list of TimeSyncException.
+type TimeSyncExceptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TimeSyncException `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TimeSyncException{}, &TimeSyncExceptionList{})
+}
@@ -0,0 +1,633 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InjectionTarget selects which objects the webhook adds the sidecar to.
+// +kubebuilder:validation:Enum=Pods;Workloads;PodsAndWorkloads
+type InjectionTarget string
+
+const (
+	// InjectionTargetPods injects the sidecar into Pods at creation.
+	InjectionTargetPods InjectionTarget = "Pods"
+	// InjectionTargetWorkloads injects the sidecar into the pod templates of
+	// Deployments, StatefulSets, DaemonSets, Jobs and CronJobs.
+	InjectionTargetWorkloads InjectionTarget = "Workloads"
+	// InjectionTargetPodsAndWorkloads does both.
+	InjectionTargetPodsAndWorkloads InjectionTarget = "PodsAndWorkloads"
+)
+
+// OverridableField names a TimeSyncPolicy setting that tenants may override
+// with a NamespaceTimeSyncPolicy.
+// +kubebuilder:validation:Enum=Enable;Image;InjectionTarget
+type OverridableField string
+
+const (
+	// OverridableFieldEnable lets tenants opt their namespace out.
+	OverridableFieldEnable OverridableField = "Enable"
+	// OverridableFieldImage lets tenants choose the sidecar image.
+	OverridableFieldImage OverridableField = "Image"
+	// OverridableFieldInjectionTarget lets tenants choose the injection target.
+	OverridableFieldInjectionTarget OverridableField = "InjectionTarget"
+)
+
+// Backend is the time synchronization implementation run by the sidecar.
+// +kubebuilder:validation:Enum=Generic;Chrony;Agent
+type Backend string
+
+const (
+	// BackendGeneric runs the template image with the template args as-is.
+	BackendGeneric Backend = "Generic"
+	// BackendChrony runs chronyd in the foreground.
+	BackendChrony Backend = "Chrony"
+	// BackendAgent runs the timesync node agent client.
+	BackendAgent Backend = "Agent"
+)
+
+// ClockAdjustment is how the sidecar may change the clock.
+// +kubebuilder:validation:Enum=None;Slew;Step
+type ClockAdjustment string
+
+const (
+	// ClockAdjustmentNone only measures the offset and never touches the clock.
+	ClockAdjustmentNone ClockAdjustment = "None"
+	// ClockAdjustmentSlew corrects the clock gradually. It needs CAP_SYS_TIME.
+	ClockAdjustmentSlew ClockAdjustment = "Slew"
+	// ClockAdjustmentStep also steps the clock when the offset is large. It
+	// needs CAP_SYS_TIME.
+	ClockAdjustmentStep ClockAdjustment = "Step"
+)
+
+// PodSecurityAction decides how the webhook reacts when the sidecar needs
+// settings that the namespace's Pod Security Admission level forbids.
+// +kubebuilder:validation:Enum=Downgrade;Refuse
+type PodSecurityAction string
+
+const (
+	// PodSecurityActionDowngrade drops the forbidden settings and records a
+	// warning on the admitted object.
+	PodSecurityActionDowngrade PodSecurityAction = "Downgrade"
+	// PodSecurityActionRefuse rejects the object instead.
+	PodSecurityActionRefuse PodSecurityAction = "Refuse"
+)
+
+// QuotaAction decides how the webhook reacts when the sidecar would break the
+// namespace's LimitRange or push a pod over its ResourceQuota.
+// +kubebuilder:validation:Enum=Warn;Refuse
+type QuotaAction string
+
+const (
+	// QuotaActionWarn injects the sidecar and returns an admission warning.
+	QuotaActionWarn QuotaAction = "Warn"
+	// QuotaActionRefuse rejects the object instead.
+	QuotaActionRefuse QuotaAction = "Refuse"
+)
+
+// PolicyMode decides whether a policy changes the objects it selects.
+// +kubebuilder:validation:Enum=Enforce;Audit
+type PolicyMode string
+
+const (
+	// PolicyModeEnforce injects the sidecar.
+	PolicyModeEnforce PolicyMode = "Enforce"
+	// PolicyModeAudit leaves objects unchanged and only records what the
+	// webhook would have done.
+	PolicyModeAudit PolicyMode = "Audit"
+)
+
+// Enforcement decides whether pods may run without the sidecar a policy
+// injects.
+// +kubebuilder:validation:Enum=Optional;Require
+type Enforcement string
+
+const (
+	// EnforcementOptional admits pods whatever happened to their sidecar.
+	EnforcementOptional Enforcement = "Optional"
+	// EnforcementRequire rejects pods the policy injects that reach the end
+	// of admission without its sidecar, or with another image.
+	EnforcementRequire Enforcement = "Require"
+)
+
+// SkipReason names a kind of pod that never receives the sidecar.
+// +kubebuilder:validation:Enum=Windows;HostNetwork;MirrorPod;DaemonSet;NodeDaemon
+type SkipReason string
+
+const (
+	// SkipReasonWindows skips pods whose spec.os.name is windows, which
+	// cannot run the Linux sidecar.
+	SkipReasonWindows SkipReason = "Windows"
+	// SkipReasonHostNetwork skips pods in the host network namespace.
+	SkipReasonHostNetwork SkipReason = "HostNetwork"
+	// SkipReasonMirrorPod skips the mirror pods of static pods, which the
+	// kubelet manages from its own manifests.
+	SkipReasonMirrorPod SkipReason = "MirrorPod"
+	// SkipReasonDaemonSet skips pods owned by a DaemonSet.
+	SkipReasonDaemonSet SkipReason = "DaemonSet"
+	// SkipReasonNodeDaemon skips pods scheduled onto nodes that already run a
+	// node-level time daemon.
+	SkipReasonNodeDaemon SkipReason = "NodeDaemon"
+)
+
+// SidecarTemplate describes the injected timesync container.
+type SidecarTemplate struct {
+	Image string `json:"image"`
+
+	// Args are passed to the sidecar after any backend-specific arguments.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env is added to the sidecar environment.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources are the compute resources of the sidecar. When unset, small
+	// defaults are used, adjusted to the namespace's LimitRange.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Capabilities are added to the sidecar, which otherwise drops all of
+	// them. Anything but NET_BIND_SERVICE makes the sidecar run as root and
+	// is only admitted in namespaces whose Pod Security level allows it.
+	// +listType=set
+	// +optional
+	Capabilities []corev1.Capability `json:"capabilities,omitempty"`
+
+	// ReadinessProbe replaces the readiness probe the backend generates,
+	// which passes while the clock is synchronized.
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe replaces the liveness probe the backend generates, which
+	// restarts the sidecar when the clock stays unsynchronized.
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// MetricsPort is the port the sidecar serves Prometheus metrics on at
+	// /metrics. The Agent backend defaults to 9123; set it for images that
+	// bundle an exporter, such as chrony_exporter. The controller creates a
+	// PodMonitor for policies whose sidecar exposes metrics.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+}
+
+// ImagePolicy decides how the sidecar image reference is resolved.
+type ImagePolicy struct {
+	// PinDigest makes the controller resolve the template image to a digest
+	// and the webhook inject that digest instead of the mutable tag.
+	// +optional
+	PinDigest bool `json:"pinDigest,omitempty"`
+
+	// PublicKey is a PEM-encoded public key. When set, the controller only
+	// pins digests that carry a cosign signature made with the matching
+	// private key, and the sidecar is not injected until one is verified.
+	// Setting it implies PinDigest.
+	// +optional
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// ImagePullSecret references a Secret that the controller copies into every
+// matched namespace so that the sidecar image can be pulled there.
+type ImagePullSecret struct {
+	// Name of the source Secret. The copies use the same name.
+	Name string `json:"name"`
+
+	// Namespace of the source Secret.
+	Namespace string `json:"namespace"`
+}
+
+// ClockSkewSLO bounds how far the clocks of injected pods may drift. Offsets
+// are reported by the sidecars or node agents through pod annotations.
+type ClockSkewSLO struct {
+	// MaxOffset is the largest absolute clock offset a pod may report.
+	// +optional
+	MaxOffset *metav1.Duration `json:"maxOffset,omitempty"`
+
+	// MaxUnsyncedDuration is the longest a pod may go without reporting a
+	// successful synchronization.
+	// +optional
+	MaxUnsyncedDuration *metav1.Duration `json:"maxUnsyncedDuration,omitempty"`
+}
+
+// Rollout limits injection to a fraction of pods, optionally growing it on a
+// schedule.
+type Rollout struct {
+	// Percentage of pods that receive the sidecar. Pods are picked by a hash
+	// of their controlling owner, so all pods of a ReplicaSet, StatefulSet or
+	// Job are treated alike. With steps, this is the starting percentage.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentage int32 `json:"percentage"`
+
+	// Steps raise the percentage over time. The controller moves to each
+	// step once the previous percentage has been in effect for its after
+	// duration, and holds while the ClockSkewExceeded condition is True.
+	// +optional
+	Steps []RolloutStep `json:"steps,omitempty"`
+}
+
+// RolloutStep is a percentage a rollout moves to after a delay.
+type RolloutStep struct {
+	// Percentage of pods that receive the sidecar from this step on.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentage int32 `json:"percentage"`
+
+	// After is how long the previous percentage stays in effect before this
+	// step starts.
+	After metav1.Duration `json:"after"`
+}
+
+// ActiveWindow is a recurring period during which changes to a policy take
+// effect.
+type ActiveWindow struct {
+	// Schedule is a cron expression (minute, hour, day of month, month, day
+	// of week) for when the window opens, such as "0 22 * * 6".
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone the schedule is read in, such as
+	// "Europe/Paris". UTC when unset.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// TimeSyncPolicySpec defines the desired state of TimeSyncPolicy.
+type TimeSyncPolicySpec struct {
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	Enable            bool                 `json:"enable"`
+
+	// Mode Audit makes the webhook annotate selected objects and emit an
+	// Event describing the injection instead of performing it, so that a
+	// policy can be rolled out before it mutates anything.
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode PolicyMode `json:"mode,omitempty"`
+
+	// Enforcement Require makes a validating webhook reject the pods the
+	// policy injects when they lack its sidecar by the end of admission, for
+	// example because another mutating webhook removed it, or when the
+	// sidecar runs another image. Only pods the policy injects directly are
+	// checked, so it has no effect with injectionTarget Workloads.
+	// +kubebuilder:default=Optional
+	// +optional
+	Enforcement Enforcement `json:"enforcement,omitempty"`
+
+	// Template describes the injected sidecar container.
+	Template SidecarTemplate `json:"template"`
+
+	// ImagePolicy pins and optionally verifies the template image.
+	// +optional
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
+	// ImagePullSecrets are copied into every matched namespace, kept in sync
+	// with their source, and added to the imagePullSecrets of injected pods.
+	// The controller also logs in with them to pin and verify the template
+	// image.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ImagePullSecrets []ImagePullSecret `json:"imagePullSecrets,omitempty"`
+
+	// Backend selects the time synchronization implementation the sidecar
+	// runs, which decides its default arguments.
+	// +kubebuilder:default=Generic
+	// +optional
+	Backend Backend `json:"backend,omitempty"`
+
+	// ClockAdjustment decides whether the sidecar may change the clock. Slew
+	// and Step grant CAP_SYS_TIME, but only in namespaces permitted by a
+	// ClockAdjustmentAllowlist; elsewhere the sidecar falls back to None.
+	// +kubebuilder:default=None
+	// +optional
+	ClockAdjustment ClockAdjustment `json:"clockAdjustment,omitempty"`
+
+	// PodSecurityAction decides what happens when the sidecar needs settings
+	// that the Pod Security Admission level enforced on the namespace forbids.
+	// +kubebuilder:default=Downgrade
+	// +optional
+	PodSecurityAction PodSecurityAction `json:"podSecurityAction,omitempty"`
+
+	// QuotaAction decides what happens when the sidecar violates the
+	// namespace's LimitRange or would push a pod over its ResourceQuota.
+	// +kubebuilder:default=Warn
+	// +optional
+	QuotaAction QuotaAction `json:"quotaAction,omitempty"`
+
+	// ReadinessGate adds a readiness gate to injected pods whose condition the
+	// controller keeps in line with the sidecar's readiness, so that a pod
+	// only becomes ready once its clock is synchronized.
+	// +optional
+	ReadinessGate bool `json:"readinessGate,omitempty"`
+
+	// SLO is checked against the offsets injected pods report. Violations
+	// set the ClockSkewExceeded condition and, when the Prometheus Operator
+	// is installed, fire the alerts of a generated PrometheusRule.
+	// +optional
+	SLO *ClockSkewSLO `json:"slo,omitempty"`
+
+	// Suspend freezes the policy: the webhook keeps injecting with the spec
+	// last applied, and neither spec changes nor rollout steps take effect
+	// until it is cleared.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// ActiveWindows restricts when spec changes and rollout steps take
+	// effect. Outside every window the webhook keeps injecting with the spec
+	// last applied. Changes take effect at any time when unset.
+	// +optional
+	ActiveWindows []ActiveWindow `json:"activeWindows,omitempty"`
+
+	// RevisionHistoryLimit is how many old revisions of the spec are kept to
+	// roll back to. Revisions that pods still run are never pruned.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// RollbackTo restores the spec of an earlier revision, as listed in
+	// status.revisions. The controller replaces the spec with it and clears
+	// the field.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+
+	// Rollout injects the sidecar into only a fraction of the selected pods.
+	// Every selected pod is injected when unset.
+	// +optional
+	Rollout *Rollout `json:"rollout,omitempty"`
+
+	// Skip lists the kinds of pods that never receive the sidecar, even when
+	// the policy selects them.
+	// +kubebuilder:default={Windows,HostNetwork,MirrorPod,DaemonSet,NodeDaemon}
+	// +listType=set
+	// +optional
+	Skip []SkipReason `json:"skip,omitempty"`
+
+	// NodeDaemonLabel is the node label that marks nodes already running a
+	// node-level time daemon. Pods whose nodeSelector requires it are skipped
+	// when skip includes NodeDaemon.
+	// +kubebuilder:default="sync.example.com/node-daemon"
+	// +optional
+	NodeDaemonLabel string `json:"nodeDaemonLabel,omitempty"`
+
+	// Priority decides between enabled policies that select the same pod.
+	// The highest priority wins; ties are broken by policy name.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// PodSelector further restricts injection to pods with matching labels.
+	// When unset, every pod in a selected namespace matches.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// MatchConditions further restricts injection to pods for which every
+	// CEL expression evaluates to true, for what label selectors cannot
+	// express. The expressions see the pod as object, its namespace as
+	// namespaceObject and the admission request as request, whose userInfo
+	// names who is creating the pod. They are evaluated at admission only.
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	MatchConditions []MatchCondition `json:"matchConditions,omitempty"`
+
+	// ExcludeNamespaces lists namespaces that never match, even if selected
+	// by NamespaceSelector.
+	// +listType=set
+	// +optional
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// ExcludePods lists pods that never match, as "name" or "namespace/name".
+	// A trailing "*" matches a name prefix, which also covers pods created
+	// from a generateName.
+	// +listType=set
+	// +optional
+	ExcludePods []string `json:"excludePods,omitempty"`
+
+	// InjectionTarget selects whether the sidecar is added to Pods, to
+	// workload pod templates so it shows up in the workload spec, or to both.
+	// +kubebuilder:default=Pods
+	// +optional
+	InjectionTarget InjectionTarget `json:"injectionTarget,omitempty"`
+
+	// AllowedOverrides lists the fields that a NamespaceTimeSyncPolicy in a
+	// matched namespace may override. Fields not listed are locked.
+	// +listType=set
+	// +optional
+	AllowedOverrides []OverridableField `json:"allowedOverrides,omitempty"`
+}
+
+// MatchCondition is a named CEL expression that a pod must satisfy.
+type MatchCondition struct {
+	// Name identifies the condition in status messages and decision traces.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Expression is a CEL expression that evaluates to a bool, such as
+	// "!object.spec.containers.exists(c, c.name == 'ntpd')".
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+}
+
+// Condition types reported on TimeSyncPolicy.
+const (
+	// ConditionReady is True when the policy is valid and has been applied.
+	ConditionReady = "Ready"
+	// ConditionClockAdjustmentPermitted is False when a matched namespace is
+	// not allowed the requested clockAdjustment.
+	ConditionClockAdjustmentPermitted = "ClockAdjustmentPermitted"
+	// ConditionImagePullSecretsSynced is True when every image pull secret
+	// has been copied into the matched namespaces.
+	ConditionImagePullSecretsSynced = "ImagePullSecretsSynced"
+	// ConditionImageResolved is True when the template image has been pinned
+	// to a digest, and verified if a public key is configured.
+	ConditionImageResolved = "ImageResolved"
+	// ConditionClockSkewExceeded is True when an injected pod is outside the
+	// policy's SLO.
+	ConditionClockSkewExceeded = "ClockSkewExceeded"
+	// ConditionSpecApplied is True when the webhook injects with the current
+	// spec, and False while suspend or activeWindows hold a change back.
+	ConditionSpecApplied = "SpecApplied"
+	// ConditionAdmissionPolicySynced is True when the ValidatingAdmissionPolicy
+	// that enforces the policy without the webhook is up to date. It is only
+	// reported for policies with enforcement Require.
+	ConditionAdmissionPolicySynced = "AdmissionPolicySynced"
+)
+
+// RolloutStatus is the progress of a rollout with steps.
+type RolloutStatus struct {
+	// Hash identifies the sidecar being rolled out: its template, including
+	// the image, its backend, clockAdjustment and imagePolicy. A change to
+	// them restarts the rollout from its first percentage; other changes to
+	// the spec carry on with the current step.
+	Hash string `json:"hash"`
+
+	// Step is the number of steps completed.
+	Step int32 `json:"step"`
+
+	// Percentage of pods the webhook currently injects.
+	Percentage int32 `json:"percentage"`
+
+	// StepStartTime is when the current percentage took effect.
+	StepStartTime metav1.Time `json:"stepStartTime"`
+
+	// Paused is true while the rollout holds because of the policy's SLO.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// RevisionStatus describes a stored revision of the spec.
+type RevisionStatus struct {
+	// Revision is the number of the revision, increasing with every spec
+	// change. It is what rollbackTo refers to.
+	Revision int64 `json:"revision"`
+
+	// Hash identifies the spec of the revision. Injected pods carry it in the
+	// sync.example.com/policy-revision label.
+	Hash string `json:"hash"`
+
+	// Pods is the number of matched pods running the revision.
+	Pods int `json:"pods"`
+}
+
+// TimeSyncPolicyStatus defines the observed state of TimeSyncPolicy.
+type TimeSyncPolicyStatus struct {
+	// ObservedGeneration is the generation last processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// MatchedNamespaces is the number of namespaces selected by the policy.
+	MatchedNamespaces int `json:"matchedNamespaces"`
+
+	// SelectedPods is the number of existing pods in matched namespaces that
+	// the policy's podSelector and excludePods select. It only counts by
+	// selector: skip rules, exceptions, the rollout or a policy of higher
+	// priority may still keep the sidecar out of those pods.
+	// +optional
+	SelectedPods int `json:"selectedPods,omitempty"`
+
+	// ResolvedImage is the template image pinned to the digest the webhook
+	// injects, as image@digest.
+	// +optional
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+
+	// ImageResolvedTime is when ResolvedImage was last refreshed.
+	// +optional
+	ImageResolvedTime *metav1.Time `json:"imageResolvedTime,omitempty"`
+
+	// UnpermittedNamespaces lists the matched namespaces that no
+	// ClockAdjustmentAllowlist permits to adjust the clock.
+	// +listType=set
+	// +optional
+	UnpermittedNamespaces []string `json:"unpermittedNamespaces,omitempty"`
+
+	// OutOfSLOPods is the number of injected pods outside the policy's SLO.
+	// +optional
+	OutOfSLOPods int `json:"outOfSLOPods,omitempty"`
+
+	// AuditedPods is the number of selected pods that an Audit mode policy
+	// would have injected when they were admitted, as the webhook recorded in
+	// their sync.example.com/would-inject annotation.
+	// +optional
+	AuditedPods int `json:"auditedPods,omitempty"`
+
+	// ActiveExceptions lists the unexpired TimeSyncExceptions that exempt
+	// pods of matched namespaces from the policy.
+	// +listType=set
+	// +optional
+	ActiveExceptions []string `json:"activeExceptions,omitempty"`
+
+	// AppliedSpec is the spec the webhook injects with while suspend or
+	// activeWindows hold back changes. Its schema is left out so that the
+	// CRD does not carry the spec schema twice.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	AppliedSpec *TimeSyncPolicySpec `json:"appliedSpec,omitempty"`
+
+	// AppliedGeneration is the generation of AppliedSpec.
+	// +optional
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+
+	// NextWindow is when the next active window opens.
+	// +optional
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+
+	// Rollout is the progress of the policy's rollout steps.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// CurrentRevision is the hash of the revision the webhook injects.
+	// +optional
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
+	// Revisions lists the stored revisions of the spec, newest first.
+	// +listType=map
+	// +listMapKey=revision
+	// +optional
+	Revisions []RevisionStatus `json:"revisions,omitempty"`
+
+	// Conditions describe the current state of the policy.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Enabled",type=boolean,JSONPath=`.spec.enable`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Backend",type=string,JSONPath=`.spec.backend`
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Namespaces",type=integer,JSONPath=`.status.matchedNamespaces`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+
+// TimeSyncPolicy is the Schema for the timesyncpolicies API.
+type TimeSyncPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TimeSyncPolicySpec   `json:"spec,omitempty"`
+	Status TimeSyncPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TimeSyncPolicyList contains a list of TimeSyncPolicy.
+type TimeSyncPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TimeSyncPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TimeSyncPolicy{}, &TimeSyncPolicyList{})
+}
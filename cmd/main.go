@@ -17,20 +17,27 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -38,8 +45,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
 	"github.com/Septimus4/timesync-operator/internal/controller"
+	"github.com/Septimus4/timesync-operator/internal/registry"
+	"github.com/Septimus4/timesync-operator/internal/tracing"
 	webhookcorev1 "github.com/Septimus4/timesync-operator/internal/webhook/v1"
+	webhooksyncv1beta1 "github.com/Septimus4/timesync-operator/internal/webhook/v1beta1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -52,6 +63,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(syncv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(syncv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -65,6 +77,11 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
+	var imageRefreshInterval, registryTimeout time.Duration
+	var tracingOpts tracing.Options
+	var maxConcurrentReconciles, requeueBurst int
+	var requeueBaseDelay, requeueMaxDelay time.Duration
+	var requeueQPS float64
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -82,6 +99,26 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&imageRefreshInterval, "image-refresh-interval", controller.DefaultImageRefreshInterval,
+		"How often sidecar images pinned to a digest are resolved again.")
+	flag.DurationVar(&registryTimeout, "registry-timeout", 30*time.Second,
+		"The timeout for requests to container registries.")
+	flag.StringVar(&tracingOpts.Endpoint, "otlp-endpoint", "",
+		"The host:port of an OTLP gRPC collector to export traces to. Tracing is disabled when empty.")
+	flag.BoolVar(&tracingOpts.Insecure, "otlp-insecure", false,
+		"If set, traces are exported to the OTLP collector without TLS.")
+	flag.Float64Var(&tracingOpts.SampleRatio, "trace-sample-ratio", 1,
+		"The fraction of traces not started by the API server that are sampled.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"How many TimeSyncPolicies are reconciled in parallel.")
+	flag.DurationVar(&requeueBaseDelay, "requeue-base-delay", 5*time.Millisecond,
+		"The first delay before a failed TimeSyncPolicy is reconciled again; it doubles on every failure.")
+	flag.DurationVar(&requeueMaxDelay, "requeue-max-delay", 1000*time.Second,
+		"The longest delay before a failed TimeSyncPolicy is reconciled again.")
+	flag.Float64Var(&requeueQPS, "requeue-qps", 10,
+		"The overall rate of requeued TimeSyncPolicy reconciles per second.")
+	flag.IntVar(&requeueBurst, "requeue-burst", 100,
+		"The burst of requeued TimeSyncPolicy reconciles allowed above --requeue-qps.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -90,6 +127,17 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -186,6 +234,10 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "58915666.example.com",
+		// Only cache the Secrets the controller copies and the policy
+		// revisions it stores, not every Secret and ControllerRevision in
+		// the cluster, and only the parts of pods the controllers read.
+		Cache: cacheOptions(os.Getenv("POD_NAMESPACE")),
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -204,18 +256,49 @@ func main() {
 	}
 
 	if err = (&controller.TimeSyncPolicyReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		APIReader:               mgr.GetAPIReader(),
+		Images:                  &registry.Client{HTTPClient: &http.Client{Timeout: registryTimeout}},
+		ImageRefreshInterval:    imageRefreshInterval,
+		Recorder:                mgr.GetEventRecorderFor("timesyncpolicy-controller"),
+		MonitoringNamespace:     os.Getenv("POD_NAMESPACE"),
+		RevisionNamespace:       os.Getenv("POD_NAMESPACE"),
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		RateLimiter:             controller.NewRateLimiter(requeueBaseDelay, requeueMaxDelay, requeueQPS, requeueBurst),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TimeSyncPolicy")
+		os.Exit(1)
+	}
+	if err = (&controller.TimeSyncExceptionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("timesyncexception-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TimeSyncException")
+		os.Exit(1)
+	}
+	if err = (&controller.PodReadinessReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "TimeSyncPolicy")
+		setupLog.Error(err, "unable to create controller", "controller", "PodReadiness")
 		os.Exit(1)
 	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err = webhookcorev1.SetupPodWebhookWithManager(mgr); err != nil {
+		if err = webhookcorev1.SetupPodWebhookWithManager(mgr, os.Getenv("POD_NAMESPACE")); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
 			os.Exit(1)
 		}
+		if err = webhookcorev1.SetupWorkloadWebhooksWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Workload")
+			os.Exit(1)
+		}
+		if err = webhooksyncv1beta1.SetupTimeSyncPolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TimeSyncPolicy")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -247,6 +330,22 @@ func main() {
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
+		_ = shutdownTracing(context.Background())
 		os.Exit(1)
 	}
 }
+
+// cacheOptions limits the cache to the Secrets the controller copies and to
+// the ControllerRevisions in revisionNamespace, and strips cached Pods.
+func cacheOptions(revisionNamespace string) cache.Options {
+	byObject := map[client.Object]cache.ByObject{
+		&corev1.Secret{}: {Label: controller.ManagedSecrets()},
+		&corev1.Pod{}:    {Transform: controller.CachedPod},
+	}
+	if revisionNamespace != "" {
+		byObject[&appsv1.ControllerRevision{}] = cache.ByObject{
+			Namespaces: map[string]cache.Config{revisionNamespace: {}},
+		}
+	}
+	return cache.Options{ByObject: byObject}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingadmissionpolicies;validatingadmissionpolicybindings,verbs=get;list;watch;create;update;patch;delete
+
+var validatingAdmissionPolicyGVK = admissionregistrationv1.SchemeGroupVersion.WithKind("ValidatingAdmissionPolicy")
+
+// syncAdmissionPolicy keeps a ValidatingAdmissionPolicy and its binding,
+// named after the policy, that reject the pods the webhook would have to
+// inject but that come without a sidecar. They enforce the policy while the
+// webhook is unavailable. Only applied policies with enforcement Require get
+// them, and only once their rollout reaches every pod, since the API server
+// cannot tell which pods a partial rollout leaves out, and once a signed
+// image has been verified.
+func (r *TimeSyncPolicyReconciler) syncAdmissionPolicy(
+	ctx context.Context,
+	tsp *syncv1beta1.TimeSyncPolicy,
+	namespaces []*corev1.Namespace,
+	exceptions []syncv1beta1.TimeSyncException,
+	now time.Time,
+) error {
+	applied, ok := policy.Applied(tsp, now)
+	enforced := ok && enforcesSidecar(applied)
+
+	gvk := validatingAdmissionPolicyGVK
+	if _, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			setAdmissionPolicyCondition(tsp, "SyncFailed", err.Error())
+			return err
+		}
+		if enforced {
+			setAdmissionPolicyCondition(tsp, "Unsupported",
+				"The API server does not serve admissionregistration.k8s.io/v1 ValidatingAdmissionPolicies; only the webhook enforces the policy")
+		} else {
+			meta.RemoveStatusCondition(&tsp.Status.Conditions, syncv1beta1.ConditionAdmissionPolicySynced)
+		}
+		return nil
+	}
+
+	if !enforced {
+		meta.RemoveStatusCondition(&tsp.Status.Conditions, syncv1beta1.ConditionAdmissionPolicySynced)
+		return r.deleteAdmissionPolicy(ctx, tsp)
+	}
+	if percentage := policy.RolloutPercentage(applied); percentage < 100 {
+		setAdmissionPolicyCondition(tsp, "RolloutInProgress",
+			fmt.Sprintf("The sidecar is rolled out to %d%% of pods; only the webhook enforces the policy", percentage))
+		return r.deleteAdmissionPolicy(ctx, tsp)
+	}
+
+	// The webhook injects nothing until a signed image has been verified, so
+	// requiring the sidecar would reject every pod in the meantime.
+	if policy.VerifiesSignature(applied.Spec.ImagePolicy) &&
+		!meta.IsStatusConditionTrue(tsp.Status.Conditions, syncv1beta1.ConditionImageResolved) {
+		setAdmissionPolicyCondition(tsp, "ImageUnverified",
+			"The sidecar image has no verified digest yet; only the webhook enforces the policy")
+		return r.deleteAdmissionPolicy(ctx, tsp)
+	}
+
+	optedOut, err := r.admissionOptOuts(ctx, applied, namespaces, now)
+	if err == nil {
+		err = r.applyAdmissionPolicy(ctx, tsp, applied, policy.ExemptionExpression(applied, exceptions, optedOut, now))
+	}
+	if err != nil {
+		setAdmissionPolicyCondition(tsp, "SyncFailed", err.Error())
+		return err
+	}
+	meta.SetStatusCondition(&tsp.Status.Conditions, metav1.Condition{
+		Type:               syncv1beta1.ConditionAdmissionPolicySynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "ValidatingAdmissionPolicy timesync-" + tsp.Name + " requires the sidecar",
+		ObservedGeneration: tsp.Generation,
+	})
+	return nil
+}
+
+// enforcesSidecar reports whether the policy requires the sidecar on pods.
+func enforcesSidecar(p *syncv1beta1.TimeSyncPolicy) bool {
+	cfg := policy.Config{Mode: p.Spec.Mode, Enforcement: p.Spec.Enforcement, Target: p.Spec.InjectionTarget}
+	return p.Spec.Enable && cfg.Requires()
+}
+
+func setAdmissionPolicyCondition(tsp *syncv1beta1.TimeSyncPolicy, reason, message string) {
+	meta.SetStatusCondition(&tsp.Status.Conditions, metav1.Condition{
+		Type:               syncv1beta1.ConditionAdmissionPolicySynced,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: tsp.Generation,
+	})
+}
+
+// admissionOptOuts returns the matched namespaces where the webhook would not
+// require the policy's sidecar: those a higher priority policy takes, or
+// whose NamespaceTimeSyncPolicies disable injection or pod injection. It
+// errs on the side of exempting a namespace when another policy only takes
+// some of its pods.
+func (r *TimeSyncPolicyReconciler) admissionOptOuts(
+	ctx context.Context,
+	applied *syncv1beta1.TimeSyncPolicy,
+	namespaces []*corev1.Namespace,
+	now time.Time,
+) ([]string, error) {
+	var policies syncv1beta1.TimeSyncPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		return nil, err
+	}
+	var overrides syncv1alpha1.NamespaceTimeSyncPolicyList
+	if err := r.List(ctx, &overrides); err != nil {
+		return nil, err
+	}
+	byNamespace := map[string][]syncv1alpha1.NamespaceTimeSyncPolicy{}
+	for _, o := range overrides.Items {
+		byNamespace[o.Namespace] = append(byNamespace[o.Namespace], o)
+	}
+
+	candidates := policy.AppliedPolicies(policies.Items, now)
+	// The reconciled policy may be newer than the cached list.
+	for i := range candidates {
+		if candidates[i].Name == applied.Name {
+			candidates[i] = *applied
+		}
+	}
+	var optedOut []string
+	for _, ns := range namespaces {
+		d := policy.ApplyOverrides(policy.Resolve(candidates, ns, nil), byNamespace[ns.Name])
+		if !d.Inject() || d.Config.PolicyName != applied.Name || !d.Config.Requires() {
+			optedOut = append(optedOut, ns.Name)
+		}
+	}
+	return optedOut, nil
+}
+
+// applyAdmissionPolicy creates or updates the ValidatingAdmissionPolicy and
+// binding for the policy, exempting the pods matched by exemption.
+func (r *TimeSyncPolicyReconciler) applyAdmissionPolicy(
+	ctx context.Context,
+	tsp, applied *syncv1beta1.TimeSyncPolicy,
+	exemption string,
+) error {
+	vap := &admissionregistrationv1.ValidatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "timesync-" + tsp.Name}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, vap, func() error {
+		setManagedLabels(vap, tsp)
+		vap.Spec = admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			FailurePolicy: ptr.To(admissionregistrationv1.Fail),
+			MatchConstraints: &admissionregistrationv1.MatchResources{
+				NamespaceSelector: applied.Spec.NamespaceSelector.DeepCopy(),
+				ObjectSelector:    applied.Spec.PodSelector.DeepCopy(),
+				ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{{
+					RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+						Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{""},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"pods"},
+						},
+					},
+				}},
+			},
+			Variables: []admissionregistrationv1.Variable{{Name: "exempt", Expression: exemption}},
+			Validations: []admissionregistrationv1.Validation{{
+				Expression: "variables.exempt || " + policy.SidecarRequirement,
+				Message:    fmt.Sprintf("pod must run the %s sidecar required by TimeSyncPolicy %q", policy.SidecarName, tsp.Name),
+				Reason:     ptr.To(metav1.StatusReasonForbidden),
+			}},
+		}
+		return controllerutil.SetControllerReference(tsp, vap, r.Scheme)
+	}); err != nil {
+		return err
+	}
+
+	binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{ObjectMeta: metav1.ObjectMeta{Name: vap.Name}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, binding, func() error {
+		setManagedLabels(binding, tsp)
+		binding.Spec = admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        vap.Name,
+			ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+		}
+		return controllerutil.SetControllerReference(tsp, binding, r.Scheme)
+	})
+	return err
+}
+
+// deleteAdmissionPolicy deletes the ValidatingAdmissionPolicy and binding of
+// the policy, if any.
+func (r *TimeSyncPolicyReconciler) deleteAdmissionPolicy(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy) error {
+	name := metav1.ObjectMeta{Name: "timesync-" + tsp.Name}
+	var errs []error
+	for _, obj := range []client.Object{
+		&admissionregistrationv1.ValidatingAdmissionPolicyBinding{ObjectMeta: name},
+		&admissionregistrationv1.ValidatingAdmissionPolicy{ObjectMeta: name},
+	} {
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setManagedLabels labels obj as generated by the controller for the policy.
+func setManagedLabels(obj client.Object, tsp *syncv1beta1.TimeSyncPolicy) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[PolicyLabel] = tsp.Name
+	labels[managedByLabel] = managerName
+	obj.SetLabels(labels)
+}
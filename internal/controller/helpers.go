@@ -16,13 +16,12 @@ limitations under the License.
 
 package controller
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
 
 func HasTimeSyncSidecar(pod *corev1.Pod) bool {
-	for _, c := range pod.Spec.Containers {
-		if c.Name == "timesync" {
-			return true
-		}
-	}
-	return false
+	return policy.HasSidecar(pod)
 }
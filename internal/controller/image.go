@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/registry"
+)
+
+const (
+	// DefaultImageRefreshInterval is how often pinned images are re-resolved,
+	// so that a moved tag is eventually picked up.
+	DefaultImageRefreshInterval = time.Hour
+
+	// imageRetryInterval is how soon a failed resolution is retried.
+	imageRetryInterval = time.Minute
+)
+
+// ImageResolver resolves image tags to digests and verifies their
+// signatures, logging in to private registries with creds. *registry.Client
+// implements it.
+type ImageResolver interface {
+	Resolve(ctx context.Context, image string, creds registry.Credentials) (string, error)
+	Verify(ctx context.Context, image, digest string, key crypto.PublicKey, creds registry.Credentials) error
+}
+
+// resolveImage refreshes the pinned sidecar image of the applied spec in the
+// policy status. It returns how long until the image should be resolved
+// again, or zero if only a change to the policy can make a difference.
+func (r *TimeSyncPolicyReconciler) resolveImage(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy) time.Duration {
+	spec := appliedSpec(tsp)
+	ip := spec.ImagePolicy
+	if !policy.PinsDigest(ip) {
+		tsp.Status.ResolvedImage = ""
+		tsp.Status.ImageResolvedTime = nil
+		meta.RemoveStatusCondition(&tsp.Status.Conditions, syncv1beta1.ConditionImageResolved)
+		return 0
+	}
+
+	image := spec.Template.Image
+	interval := r.ImageRefreshInterval
+	if interval <= 0 {
+		interval = DefaultImageRefreshInterval
+	}
+	current := policy.PinnedFrom(tsp.Status.ResolvedImage, image)
+	if cond := meta.FindStatusCondition(tsp.Status.Conditions, syncv1beta1.ConditionImageResolved); current &&
+		cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == tsp.Generation &&
+		tsp.Status.ImageResolvedTime != nil {
+		if age := time.Since(tsp.Status.ImageResolvedTime.Time); age < interval {
+			return interval - age
+		}
+	}
+
+	fail := func(reason string, err error, retry time.Duration) time.Duration {
+		logf.FromContext(ctx).Error(err, "Failed to pin sidecar image", "image", image, "reason", reason)
+		// A previous digest of the same image stays usable while the
+		// registry is unreachable, but never one that failed verification.
+		if !current || reason != "ResolutionFailed" {
+			tsp.Status.ResolvedImage = ""
+			tsp.Status.ImageResolvedTime = nil
+		}
+		r.setImageCondition(tsp, metav1.ConditionFalse, reason, err.Error())
+		return retry
+	}
+
+	if r.Images == nil {
+		return fail("ResolverUnavailable", fmt.Errorf("no image resolver is configured"), 0)
+	}
+	var key crypto.PublicKey
+	if policy.VerifiesSignature(ip) {
+		var err error
+		if key, err = registry.ParsePublicKey([]byte(ip.PublicKey)); err != nil {
+			return fail("InvalidPublicKey", err, 0)
+		}
+	}
+
+	creds := r.registryCredentials(ctx, spec.ImagePullSecrets)
+	digest, err := r.Images.Resolve(ctx, image, creds)
+	if err != nil {
+		return fail("ResolutionFailed", err, imageRetryInterval)
+	}
+	reason, message := "Resolved", fmt.Sprintf("Image pinned to %s", digest)
+	if key != nil {
+		if err := r.Images.Verify(ctx, image, digest, key, creds); err != nil {
+			return fail("VerificationFailed", err, imageRetryInterval)
+		}
+		reason, message = "Verified", fmt.Sprintf("Image pinned to %s with a verified signature", digest)
+	}
+
+	now := metav1.Now()
+	tsp.Status.ResolvedImage = policy.PinImage(image, digest)
+	tsp.Status.ImageResolvedTime = &now
+	r.setImageCondition(tsp, metav1.ConditionTrue, reason, message)
+	return interval
+}
+
+// registryCredentials reads the registry logins of the policy's image pull
+// secrets, so that images can be pinned and verified in the private
+// registries the sidecar is pulled from. Secrets that cannot be read are
+// skipped, as syncPullSecrets reports them.
+func (r *TimeSyncPolicyReconciler) registryCredentials(ctx context.Context, refs []syncv1beta1.ImagePullSecret) registry.Credentials {
+	creds := registry.Credentials{}
+	for _, ref := range refs {
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+		if err := r.apiReader().Get(ctx, key, &secret); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to read image pull secret for the registry", "secret", key)
+			continue
+		}
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			data, ok = secret.Data[corev1.DockerConfigKey]
+		}
+		if !ok {
+			continue
+		}
+		logins, err := registry.ParseDockerConfig(data)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to read registry logins from image pull secret", "secret", key)
+			continue
+		}
+		creds.Merge(logins)
+	}
+	return creds
+}
+
+func (r *TimeSyncPolicyReconciler) setImageCondition(
+	tsp *syncv1beta1.TimeSyncPolicy,
+	status metav1.ConditionStatus,
+	reason, message string,
+) {
+	meta.SetStatusCondition(&tsp.Status.Conditions, metav1.Condition{
+		Type:               syncv1beta1.ConditionImageResolved,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: tsp.Generation,
+	})
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/sidecar"
+)
+
+// The Prometheus Operator kinds generated for policies. The operator is
+// optional, so they are handled as unstructured data rather than imported.
+var (
+	prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+	podMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules;podmonitors,verbs=get;list;watch;create;update;patch;delete
+
+// syncMonitoring creates the PrometheusRule that alerts on the policy's SLO
+// and the PodMonitor that scrapes its sidecars in MonitoringNamespace, and
+// deletes them once the policy no longer needs them.
+func (r *TimeSyncPolicyReconciler) syncMonitoring(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy) error {
+	var ruleSpec, monitorSpec map[string]any
+	if tsp.Spec.SLO != nil {
+		ruleSpec = prometheusRuleSpec(tsp)
+	}
+	if tsp.Spec.Enable && sidecar.MetricsPort(tsp.Spec.Backend, &tsp.Spec.Template) != 0 {
+		monitorSpec = podMonitorSpec(tsp)
+	}
+	return errors.Join(
+		r.syncMonitoringObject(ctx, tsp, prometheusRuleGVK, ruleSpec),
+		r.syncMonitoringObject(ctx, tsp, podMonitorGVK, monitorSpec),
+	)
+}
+
+// syncMonitoringObject creates or updates the object of kind gvk named after
+// the policy with spec, or deletes it when spec is nil. It does nothing when
+// the Prometheus Operator CRDs are not installed.
+func (r *TimeSyncPolicyReconciler) syncMonitoringObject(
+	ctx context.Context,
+	tsp *syncv1beta1.TimeSyncPolicy,
+	gvk schema.GroupVersionKind,
+	spec map[string]any,
+) error {
+	if r.MonitoringNamespace == "" {
+		return nil
+	}
+	if _, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName("timesync-" + tsp.Name)
+	obj.SetNamespace(r.MonitoringNamespace)
+
+	if spec == nil {
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		setManagedLabels(obj, tsp)
+		obj.Object["spec"] = spec
+		return controllerutil.SetControllerReference(tsp, obj, r.Scheme)
+	})
+	return err
+}
+
+// podMonitorSpec returns a PodMonitor that scrapes the metrics port of the
+// sidecars injected by the policy, in every namespace.
+func podMonitorSpec(tsp *syncv1beta1.TimeSyncPolicy) map[string]any {
+	return map[string]any{
+		"selector": map[string]any{
+			"matchLabels": map[string]any{sidecar.InjectedByLabel: tsp.Name},
+		},
+		"namespaceSelector": map[string]any{"any": true},
+		"podMetricsEndpoints": []any{map[string]any{
+			"port": sidecar.MetricsPortName,
+			"path": "/metrics",
+		}},
+	}
+}
+
+// prometheusRuleSpec returns the alerting rules for the policy's SLO. They
+// read the metrics the controller exports, so they fire on the same data as
+// the ClockSkewExceeded condition.
+func prometheusRuleSpec(tsp *syncv1beta1.TimeSyncPolicy) map[string]any {
+	selector := fmt.Sprintf("{policy=%q}", tsp.Name)
+	var rules []any
+	if tsp.Spec.SLO.MaxOffset != nil {
+		rules = append(rules, alert(
+			"TimeSyncOffsetExceeded",
+			"max by (target_namespace, target_pod) (abs(timesync_offset_seconds"+selector+")) > "+
+				strconv.FormatFloat(tsp.Spec.SLO.MaxOffset.Seconds(), 'f', -1, 64),
+			"5m",
+			fmt.Sprintf("Pod {{ $labels.target_namespace }}/{{ $labels.target_pod }} has a clock offset beyond the maxOffset of TimeSyncPolicy %s.", tsp.Name),
+		))
+	}
+	rules = append(rules, alert(
+		"TimeSyncSLOViolated",
+		"timesync_slo_compliance_ratio"+selector+" < 1",
+		"15m",
+		fmt.Sprintf("{{ $value | humanizePercentage }} of the pods of TimeSyncPolicy %s are within its clock skew SLO.", tsp.Name),
+	))
+	return map[string]any{
+		"groups": []any{map[string]any{
+			"name":  "timesync-" + tsp.Name,
+			"rules": rules,
+		}},
+	}
+}
+
+func alert(name, expr, forDuration, description string) map[string]any {
+	return map[string]any{
+		"alert":       name,
+		"expr":        expr,
+		"for":         forDuration,
+		"labels":      map[string]any{"severity": "warning"},
+		"annotations": map[string]any{"description": description},
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// namespaceIndex remembers which policies matched each namespace when they
+// were last reconciled. A namespace that lost its labels no longer says which
+// policies selected it, so its events are mapped through the index as well.
+type namespaceIndex struct {
+	mu       sync.Mutex
+	policies map[string]sets.Set[string]
+}
+
+// set records that policyName matches exactly namespaces.
+func (i *namespaceIndex) set(policyName string, namespaces []string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.policies == nil {
+		i.policies = map[string]sets.Set[string]{}
+	}
+	for ns, names := range i.policies {
+		names.Delete(policyName)
+		if names.Len() == 0 {
+			delete(i.policies, ns)
+		}
+	}
+	for _, ns := range namespaces {
+		if i.policies[ns] == nil {
+			i.policies[ns] = sets.New[string]()
+		}
+		i.policies[ns].Insert(policyName)
+	}
+}
+
+// lookup returns the policies that matched the namespace.
+func (i *namespaceIndex) lookup(namespace string) []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return sets.List(i.policies[namespace])
+}
+
+// namespaceHandler requeues every policy a namespace event may affect: the
+// policies that select the namespace before or after the event, and those
+// that matched it at their last reconcile.
+func (r *TimeSyncPolicyReconciler) namespaceHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.enqueueNamespacePolicies(ctx, q, e.Object)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.enqueueNamespacePolicies(ctx, q, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.enqueueNamespacePolicies(ctx, q, e.Object)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.enqueueNamespacePolicies(ctx, q, e.Object)
+		},
+	}
+}
+
+func (r *TimeSyncPolicyReconciler) enqueueNamespacePolicies(
+	ctx context.Context,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	objs ...client.Object,
+) {
+	var policies syncv1beta1.TimeSyncPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list TimeSyncPolicies for a namespace event")
+		return
+	}
+
+	names := sets.New[string]()
+	for _, obj := range objs {
+		ns, ok := obj.(*corev1.Namespace)
+		if !ok {
+			continue
+		}
+		names.Insert(r.index.lookup(ns.Name)...)
+		for i := range policies.Items {
+			if matched, _ := policy.MatchesNamespace(&policies.Items[i], ns); matched {
+				names.Insert(policies.Items[i].Name)
+			}
+		}
+	}
+	for name := range names {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+}
+
+// deletionStartedPredicate passes updates that mark an object for deletion.
+// A Terminating namespace no longer counts towards its policies, but its
+// Delete event may be a long time coming.
+func deletionStartedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CachedPod is the cache transform of Pods. It keeps what the controllers
+// read of a pod: its metadata, the names of its containers, its readiness
+// gates, and its start time and conditions. The rest of the spec and status,
+// along with the managed fields, is what would make caching every pod in the
+// cluster expensive.
+func CachedPod(obj any) (any, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	pod.ManagedFields = nil
+	containers := make([]corev1.Container, 0, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, corev1.Container{Name: c.Name})
+	}
+	pod.Spec = corev1.PodSpec{
+		Containers:     containers,
+		ReadinessGates: pod.Spec.ReadinessGates,
+	}
+	pod.Status = corev1.PodStatus{
+		StartTime:  pod.Status.StartTime,
+		Conditions: pod.Status.Conditions,
+	}
+	return pod, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/sidecar"
+)
+
+var _ = Describe("CachedPod", func() {
+	It("keeps only what the controllers read", func() {
+		started := metav1.NewTime(time.Now())
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          "web",
+				Namespace:     "apps",
+				Labels:        map[string]string{sidecar.InjectedByLabel: "policy"},
+				Annotations:   map[string]string{policy.OffsetAnnotation: "0.001"},
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest", Env: []corev1.EnvVar{{Name: "A", Value: "b"}}},
+					{Name: policy.SidecarName, Image: "timesync:latest"},
+				},
+				ReadinessGates: []corev1.PodReadinessGate{{ConditionType: sidecar.ReadinessGate}},
+				NodeName:       "node-1",
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				StartTime:  &started,
+				Conditions: []corev1.PodCondition{{Type: sidecar.ReadinessGate, Status: corev1.ConditionTrue}},
+			},
+		}
+
+		obj, err := CachedPod(pod)
+		Expect(err).NotTo(HaveOccurred())
+		cached := obj.(*corev1.Pod)
+		Expect(cached.Labels).To(HaveKey(sidecar.InjectedByLabel))
+		Expect(cached.Annotations).To(HaveKey(policy.OffsetAnnotation))
+		Expect(cached.ManagedFields).To(BeNil())
+		Expect(cached.Spec).To(Equal(corev1.PodSpec{
+			Containers:     []corev1.Container{{Name: "app"}, {Name: policy.SidecarName}},
+			ReadinessGates: []corev1.PodReadinessGate{{ConditionType: sidecar.ReadinessGate}},
+		}))
+		Expect(cached.Status).To(Equal(corev1.PodStatus{
+			StartTime:  &started,
+			Conditions: []corev1.PodCondition{{Type: sidecar.ReadinessGate, Status: corev1.ConditionTrue}},
+		}))
+		Expect(policy.HasSidecar(cached)).To(BeTrue())
+		Expect(hasReadinessGate(cached)).To(BeTrue())
+	})
+
+	It("leaves other objects alone", func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}}
+		Expect(CachedPod(ns)).To(BeIdenticalTo(ns))
+	})
+})
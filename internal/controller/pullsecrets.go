@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+const (
+	// PolicyLabel is set on objects the controller creates for a policy.
+	PolicyLabel = "sync.example.com/policy"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managerName    = "timesync-operator"
+)
+
+// PullSecretSyncInterval is how often policies with image pull secrets
+// re-read their sources, which are not watched.
+const PullSecretSyncInterval = 5 * time.Minute
+
+// The cache only holds the copies (see ManagedSecrets); sources are read
+// with get through the APIReader.
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+
+// ManagedSecrets selects the Secrets the controller copies for policies, the
+// only ones the manager cache needs to hold.
+func ManagedSecrets() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{managedByLabel: managerName})
+}
+
+// unsupportedSecretError is returned for a source Secret that does not hold
+// registry credentials, which are the only Secrets copied.
+type unsupportedSecretError struct {
+	key        types.NamespacedName
+	secretType corev1.SecretType
+}
+
+func (e *unsupportedSecretError) Error() string {
+	return fmt.Sprintf("secret %s has type %q; only %s and %s Secrets are copied",
+		e.key, e.secretType, corev1.SecretTypeDockerConfigJson, corev1.SecretTypeDockercfg)
+}
+
+// syncPullSecrets copies the policy's image pull secrets into the given
+// namespaces and deletes the copies that are no longer wanted. Copies are
+// owned by the policy, so they are garbage collected along with it.
+func (r *TimeSyncPolicyReconciler) syncPullSecrets(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy, namespaces []string) error {
+	wanted := sets.New[types.NamespacedName]()
+	var errs []error
+	for _, ref := range tsp.Spec.ImagePullSecrets {
+		var src corev1.Secret
+		key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+		if err := r.apiReader().Get(ctx, key, &src); err != nil {
+			errs = append(errs, fmt.Errorf("getting source secret %s: %w", key, err))
+			continue
+		}
+		if src.Type != corev1.SecretTypeDockerConfigJson && src.Type != corev1.SecretTypeDockercfg {
+			errs = append(errs, &unsupportedSecretError{key: key, secretType: src.Type})
+			continue
+		}
+		for _, ns := range namespaces {
+			if ns == ref.Namespace {
+				continue
+			}
+			wanted.Insert(types.NamespacedName{Namespace: ns, Name: ref.Name})
+			if err := r.copySecret(ctx, tsp, &src, ns); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	var copies corev1.SecretList
+	if err := r.List(ctx, &copies, client.MatchingLabels{PolicyLabel: tsp.Name}); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for i := range copies.Items {
+		if wanted.Has(client.ObjectKeyFromObject(&copies.Items[i])) {
+			continue
+		}
+		if err := r.Delete(ctx, &copies.Items[i]); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("deleting secret %s/%s: %w", copies.Items[i].Namespace, copies.Items[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// copySecret creates or updates the copy of src in namespace. A Secret of
+// the same name that the policy does not manage is left alone.
+func (r *TimeSyncPolicyReconciler) copySecret(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy, src *corev1.Secret, namespace string) error {
+	dst := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: src.Name, Namespace: namespace}}
+	// The cache only holds managed copies, so an unmanaged Secret of the
+	// same name only shows up when creating the copy.
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, dst, func() error {
+		if !dst.CreationTimestamp.IsZero() && dst.Labels[PolicyLabel] != tsp.Name {
+			return fmt.Errorf("secret %s/%s exists and is not managed by this policy", namespace, src.Name)
+		}
+		if dst.Labels == nil {
+			dst.Labels = map[string]string{}
+		}
+		dst.Labels[managedByLabel] = managerName
+		dst.Labels[PolicyLabel] = tsp.Name
+		dst.Type = src.Type
+		dst.Data = src.Data
+		return controllerutil.SetControllerReference(tsp, dst, r.Scheme)
+	})
+	if apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("secret %s/%s exists and is not managed by this policy", namespace, src.Name)
+	}
+	return err
+}
+
+// apiReader returns the reader for source Secrets, which the cache does not
+// hold.
+func (r *TimeSyncPolicyReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// map a *Secret event to the TimeSyncPolicy that owns the copy
+func (r *TimeSyncPolicyReconciler) mapSecretToPolicies(
+	_ context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	if name, ok := obj.GetLabels()[PolicyLabel]; ok {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+	}
+	return nil
+}
+
+// setPullSecretsCondition records the result of syncPullSecrets, or drops
+// the condition when the policy has no pull secrets.
+func setPullSecretsCondition(tsp *syncv1beta1.TimeSyncPolicy, err error) {
+	if len(tsp.Spec.ImagePullSecrets) == 0 && err == nil {
+		meta.RemoveStatusCondition(&tsp.Status.Conditions, syncv1beta1.ConditionImagePullSecretsSynced)
+		return
+	}
+	cond := metav1.Condition{
+		Type:               syncv1beta1.ConditionImagePullSecretsSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "Image pull secrets are present in every matched namespace",
+		ObservedGeneration: tsp.Generation,
+	}
+	if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "SyncFailed"
+		cond.Message = err.Error()
+		var unsupported *unsupportedSecretError
+		if errors.As(err, &unsupported) {
+			cond.Reason = "UnsupportedSecretType"
+		}
+	}
+	meta.SetStatusCondition(&tsp.Status.Conditions, cond)
+}
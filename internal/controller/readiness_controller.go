@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/sidecar"
+	"github.com/Septimus4/timesync-operator/internal/tracing"
+)
+
+// PodReadinessReconciler keeps the clock synchronization readiness gate of
+// injected pods in line with the clock state they report through
+// OffsetAnnotation and SyncedAtAnnotation, checked against the SLO of the
+// policy that injected them.
+type PodReadinessReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
+
+// Reconcile sets the readiness gate condition of a gated pod.
+func (r *PodReadinessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "PodReadiness.Reconcile", trace.WithAttributes(
+		attribute.String("namespace", req.Namespace), attribute.String("pod", req.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	var pod corev1.Pod
+	if err := r.Get(ctx, req.NamespacedName, &pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !hasReadinessGate(&pod) {
+		return ctrl.Result{}, nil
+	}
+
+	var slo *syncv1beta1.ClockSkewSLO
+	var tsp syncv1beta1.TimeSyncPolicy
+	if name := pod.Labels[sidecar.InjectedByLabel]; name != "" {
+		switch err := r.Get(ctx, client.ObjectKey{Name: name}, &tsp); {
+		case err == nil:
+			slo = tsp.Spec.SLO
+		case !apierrors.IsNotFound(err):
+			return ctrl.Result{}, err
+		}
+	}
+	// A pod that stops reporting only falls out of the SLO as time passes,
+	// without an event to reconcile it.
+	if slo != nil && slo.MaxUnsyncedDuration != nil {
+		result.RequeueAfter = SLOCheckInterval
+	}
+
+	want := clockSynchronizedCondition(slo, &pod, time.Now())
+	i := slices.IndexFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool {
+		return c.Type == sidecar.ReadinessGate
+	})
+	if i >= 0 && pod.Status.Conditions[i].Status == want.Status && pod.Status.Conditions[i].Reason == want.Reason {
+		return result, nil
+	}
+
+	patch := client.StrategicMergeFrom(pod.DeepCopy())
+	want.LastTransitionTime = metav1.Now()
+	if i >= 0 {
+		if pod.Status.Conditions[i].Status == want.Status {
+			want.LastTransitionTime = pod.Status.Conditions[i].LastTransitionTime
+		}
+		pod.Status.Conditions[i] = want
+	} else {
+		pod.Status.Conditions = append(pod.Status.Conditions, want)
+	}
+	if err := r.Status().Patch(ctx, &pod, patch); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logf.FromContext(ctx).Info("Clock synchronization readiness changed", "pod", req.NamespacedName,
+		"status", want.Status, "reason", want.Reason)
+	return result, nil
+}
+
+// clockSynchronizedCondition derives the readiness gate condition from the
+// clock state the pod reports, checked against slo at now. Without an SLO,
+// any reported offset counts as synchronized.
+func clockSynchronizedCondition(slo *syncv1beta1.ClockSkewSLO, pod *corev1.Pod, now time.Time) corev1.PodCondition {
+	cond := corev1.PodCondition{Type: sidecar.ReadinessGate, Status: corev1.ConditionFalse}
+	c := policy.CheckSLO(slo, pod, now)
+	switch {
+	case c.Violation != "":
+		cond.Reason = "ClockSkewExceeded"
+		cond.Message = "The pod is outside the SLO: " + c.Violation
+	case !c.Reported:
+		cond.Reason = "OffsetNotReported"
+		cond.Message = "The pod has not reported its clock offset yet"
+	default:
+		cond.Status = corev1.ConditionTrue
+		cond.Reason = "ClockSynchronized"
+		cond.Message = fmt.Sprintf("The pod reports a clock offset of %s", c.Offset)
+	}
+	return cond
+}
+
+func hasReadinessGate(pod *corev1.Pod) bool {
+	return slices.Contains(pod.Spec.ReadinessGates, corev1.PodReadinessGate{ConditionType: sidecar.ReadinessGate})
+}
+
+// SetupWithManager wires the controller to the pods that carry the gate.
+func (r *PodReadinessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("clocksync-readiness").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pod, ok := obj.(*corev1.Pod)
+			return ok && hasReadinessGate(pod)
+		}))).
+		Complete(r)
+}
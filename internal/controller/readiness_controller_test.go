@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/sidecar"
+)
+
+var _ = Describe("PodReadiness Controller", func() {
+	ctx := context.Background()
+
+	It("should follow the clock state the pod reports against the policy SLO", func() {
+		By("creating a policy with an SLO")
+		tsp := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "readiness-policy"},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				Enable:        true,
+				Template:      syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				ReadinessGate: true,
+				SLO: &syncv1beta1.ClockSkewSLO{
+					MaxOffset:           &metav1.Duration{Duration: 100 * time.Millisecond},
+					MaxUnsyncedDuration: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, tsp)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, tsp)
+
+		By("creating a pod it injected with the clock synchronization readiness gate")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gated",
+				Namespace: "default",
+				Labels:    map[string]string{sidecar.InjectedByLabel: tsp.Name},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+					{Name: "timesync", Image: "timesync:latest"},
+				},
+				ReadinessGates: []corev1.PodReadinessGate{{ConditionType: sidecar.ReadinessGate}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		reconciler := &PodReadinessReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		reconcileAndGet := func() corev1.PodCondition {
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(SLOCheckInterval))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+			for _, c := range pod.Status.Conditions {
+				if c.Type == sidecar.ReadinessGate {
+					return c
+				}
+			}
+			Fail("readiness gate condition not set")
+			return corev1.PodCondition{}
+		}
+		report := func(offset string, syncedAt time.Time) {
+			pod.Annotations = map[string]string{
+				policy.OffsetAnnotation:   offset,
+				policy.SyncedAtAnnotation: syncedAt.Format(time.RFC3339),
+			}
+			Expect(k8sClient.Update(ctx, pod)).To(Succeed())
+		}
+
+		By("reporting the gate as false before the pod reports its offset")
+		cond := reconcileAndGet()
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("OffsetNotReported"))
+
+		By("reporting the gate as true once the offset is within the SLO")
+		report("0.02", time.Now())
+		cond = reconcileAndGet()
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("ClockSynchronized"))
+
+		By("reporting the gate as false when the offset exceeds maxOffset")
+		report("0.5", time.Now())
+		cond = reconcileAndGet()
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("ClockSkewExceeded"))
+		Expect(cond.Message).To(ContainSubstring("exceeds maxOffset"))
+
+		By("reporting the gate as false when the last sync is older than maxUnsyncedDuration")
+		report("0", time.Now().Add(-2*time.Hour))
+		cond = reconcileAndGet()
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Message).To(ContainSubstring("maxUnsyncedDuration"))
+	})
+})
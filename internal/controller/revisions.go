@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+
+// listRevisions returns the ControllerRevisions stored for the policy,
+// oldest first.
+func (r *TimeSyncPolicyReconciler) listRevisions(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy) ([]appsv1.ControllerRevision, error) {
+	var list appsv1.ControllerRevisionList
+	if err := r.List(ctx, &list, client.InNamespace(r.RevisionNamespace),
+		client.MatchingLabels{PolicyLabel: tsp.Name}); err != nil {
+		return nil, err
+	}
+	revisions := list.Items
+	slices.SortFunc(revisions, func(a, b appsv1.ControllerRevision) int {
+		return cmp.Compare(a.Revision, b.Revision)
+	})
+	return revisions, nil
+}
+
+// syncRevisions stores the applied spec as the newest ControllerRevision of
+// the policy, prunes the revisions beyond revisionHistoryLimit that no pod
+// runs, and lists the rest in the status along with the number of pods
+// running each. pods counts the matched pods by revision hash.
+//
+// A spec that returns to an earlier revision renumbers that revision rather
+// than storing it twice, as Deployments do.
+func (r *TimeSyncPolicyReconciler) syncRevisions(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy, pods map[string]int) error {
+	if r.RevisionNamespace == "" || tsp.Status.AppliedSpec == nil {
+		return nil
+	}
+	spec := policy.RevisionSpec(tsp.Status.AppliedSpec)
+	hash := policy.RevisionHash(spec)
+	tsp.Status.CurrentRevision = hash
+
+	revisions, err := r.listRevisions(ctx, tsp)
+	if err != nil {
+		return err
+	}
+	var next int64 = 1
+	if len(revisions) > 0 {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+	i := slices.IndexFunc(revisions, func(rev appsv1.ControllerRevision) bool {
+		return rev.Labels[appsv1.ControllerRevisionHashLabelKey] == hash
+	})
+	switch {
+	case i < 0:
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return err
+		}
+		current := appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", tsp.Name, hash),
+				Namespace: r.RevisionNamespace,
+				Labels: map[string]string{
+					managedByLabel:                        managerName,
+					PolicyLabel:                           tsp.Name,
+					appsv1.ControllerRevisionHashLabelKey: hash,
+				},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: next,
+		}
+		if err := controllerutil.SetControllerReference(tsp, &current, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, &current); err != nil {
+			return fmt.Errorf("creating revision %d: %w", next, err)
+		}
+		logf.FromContext(ctx).Info("Stored policy revision", "revision", next, "hash", hash)
+		revisions = append(revisions, current)
+	case i < len(revisions)-1:
+		current := revisions[i]
+		current.Revision = next
+		if err := r.Update(ctx, &current); err != nil {
+			return fmt.Errorf("renumbering revision %d: %w", revisions[i].Revision, err)
+		}
+		revisions = append(slices.Delete(revisions, i, i+1), current)
+	}
+
+	// The current revision is always kept, on top of the history.
+	limit := int(ptr.Deref(tsp.Spec.RevisionHistoryLimit, 10))
+	excess := len(revisions) - 1 - limit
+	var errs []error
+	status := make([]syncv1beta1.RevisionStatus, 0, len(revisions))
+	for j := len(revisions) - 1; j >= 0; j-- {
+		rev := &revisions[j]
+		revHash := rev.Labels[appsv1.ControllerRevisionHashLabelKey]
+		if j < excess && pods[revHash] == 0 {
+			if err := r.Delete(ctx, rev); client.IgnoreNotFound(err) != nil {
+				errs = append(errs, fmt.Errorf("pruning revision %d: %w", rev.Revision, err))
+			}
+			continue
+		}
+		status = append(status, syncv1beta1.RevisionStatus{Revision: rev.Revision, Hash: revHash, Pods: pods[revHash]})
+	}
+	tsp.Status.Revisions = status
+	return errors.Join(errs...)
+}
+
+// rollback replaces the spec of the policy with that of the revision named
+// by rollbackTo and clears the field. A revision that does not exist only
+// clears it, with a warning Event.
+func (r *TimeSyncPolicyReconciler) rollback(ctx context.Context, tsp *syncv1beta1.TimeSyncPolicy) error {
+	target := *tsp.Spec.RollbackTo
+	revisions, err := r.listRevisions(ctx, tsp)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(revisions, func(rev appsv1.ControllerRevision) bool { return rev.Revision == target })
+	if i < 0 {
+		tsp.Spec.RollbackTo = nil
+		if err := r.Update(ctx, tsp); err != nil {
+			return err
+		}
+		r.event(tsp, corev1.EventTypeWarning, "RollbackRevisionNotFound",
+			fmt.Sprintf("Unable to find revision %d to roll back to", target))
+		return nil
+	}
+
+	var spec syncv1beta1.TimeSyncPolicySpec
+	if err := json.Unmarshal(revisions[i].Data.Raw, &spec); err != nil {
+		return fmt.Errorf("decoding revision %d: %w", target, err)
+	}
+	spec.RevisionHistoryLimit = tsp.Spec.RevisionHistoryLimit
+	tsp.Spec = spec
+	if err := r.Update(ctx, tsp); err != nil {
+		return err
+	}
+	logf.FromContext(ctx).Info("Rolled back policy", "revision", target)
+	r.event(tsp, corev1.EventTypeNormal, "RolledBack", fmt.Sprintf("Rolled back to revision %d", target))
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// advanceRollout moves the policy's rollout to its next step once the
+// current one has lasted long enough, holding it while the SLO is violated.
+// It returns how long until the next step is due, or zero when there is none.
+//
+// The rollout advances at most one step per call, so a controller that was
+// down for a while does not jump straight to the last percentage.
+func (r *TimeSyncPolicyReconciler) advanceRollout(tsp *syncv1beta1.TimeSyncPolicy, now time.Time) time.Duration {
+	rollout := tsp.Spec.Rollout
+	if rollout == nil || len(rollout.Steps) == 0 {
+		tsp.Status.Rollout = nil
+		return 0
+	}
+
+	// Only a change to the sidecar restarts the rollout.
+	status := tsp.Status.Rollout
+	if hash := policy.RolloutHash(&tsp.Spec); status == nil || status.Hash != hash {
+		status = &syncv1beta1.RolloutStatus{
+			Hash:          hash,
+			Percentage:    rollout.Percentage,
+			StepStartTime: metav1.NewTime(now),
+		}
+		tsp.Status.Rollout = status
+	}
+	if int(status.Step) >= len(rollout.Steps) {
+		return 0
+	}
+
+	if meta.IsStatusConditionTrue(tsp.Status.Conditions, syncv1beta1.ConditionClockSkewExceeded) {
+		if !status.Paused {
+			status.Paused = true
+			r.event(tsp, corev1.EventTypeWarning, "RolloutPaused",
+				fmt.Sprintf("Rollout paused at %d%% while pods are outside the SLO", status.Percentage))
+		}
+		return 0
+	}
+	if status.Paused {
+		// The current step starts over once the SLO has recovered.
+		status.Paused = false
+		status.StepStartTime = metav1.NewTime(now)
+		r.event(tsp, corev1.EventTypeNormal, "RolloutResumed",
+			fmt.Sprintf("Rollout resumed at %d%%", status.Percentage))
+	}
+
+	next := rollout.Steps[status.Step]
+	if wait := status.StepStartTime.Add(next.After.Duration).Sub(now); wait > 0 {
+		return wait
+	}
+	status.Step++
+	status.Percentage = next.Percentage
+	status.StepStartTime = metav1.NewTime(now)
+	r.event(tsp, corev1.EventTypeNormal, "RolloutAdvanced",
+		fmt.Sprintf("Rollout advanced to %d%% (step %d of %d)", status.Percentage, status.Step, len(rollout.Steps)))
+	if int(status.Step) < len(rollout.Steps) {
+		return rollout.Steps[status.Step].After.Duration
+	}
+	return 0
+}
+
+// event emits an Event on the policy when the reconciler has a recorder.
+func (r *TimeSyncPolicyReconciler) event(tsp *syncv1beta1.TimeSyncPolicy, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(tsp, eventType, reason, message)
+	}
+}
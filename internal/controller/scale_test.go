@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+const (
+	scaleNamespaces       = 2000
+	scalePolicies         = 200
+	scalePodsPerNamespace = 5
+)
+
+// newScaleReconciler returns a reconciler over a fake cluster of
+// scaleNamespaces namespaces, each selected by exactly one of scalePolicies
+// policies and running scalePodsPerNamespace pods.
+func newScaleReconciler(b *testing.B) *TimeSyncPolicyReconciler {
+	b.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+	if err := syncv1beta1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+
+	objs := make([]client.Object, 0, scalePolicies+scaleNamespaces*(1+scalePodsPerNamespace))
+	for i := range scalePolicies {
+		objs = append(objs, &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("policy-%d", i)},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": fmt.Sprintf("team-%d", i)}},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"timesync": "enabled"}},
+				Enable:            true,
+				Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+			},
+		})
+	}
+	for i := range scaleNamespaces {
+		name := fmt.Sprintf("ns-%d", i)
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"team": fmt.Sprintf("team-%d", i%scalePolicies)},
+		}})
+		for j := range scalePodsPerNamespace {
+			labels := map[string]string{"app": "web"}
+			if j%2 == 0 {
+				labels["timesync"] = "enabled"
+			}
+			objs = append(objs, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("pod-%d", j),
+				Namespace: name,
+				Labels:    labels,
+			}})
+		}
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&syncv1beta1.TimeSyncPolicy{}).
+		Build()
+	return &TimeSyncPolicyReconciler{Client: c, Scheme: scheme}
+}
+
+// BenchmarkReconcileAtScale reconciles every policy once per iteration.
+func BenchmarkReconcileAtScale(b *testing.B) {
+	r := newScaleReconciler(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for range b.N {
+		for i := range scalePolicies {
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: fmt.Sprintf("policy-%d", i)}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.StopTimer()
+
+	var tsp syncv1beta1.TimeSyncPolicy
+	if err := r.Get(ctx, types.NamespacedName{Name: "policy-0"}, &tsp); err != nil {
+		b.Fatal(err)
+	}
+	if want := scaleNamespaces / scalePolicies; tsp.Status.MatchedNamespaces != want {
+		b.Fatalf("got %d matched namespaces, want %d", tsp.Status.MatchedNamespaces, want)
+	}
+	if want := scaleNamespaces / scalePolicies * ((scalePodsPerNamespace + 1) / 2); tsp.Status.SelectedPods != want {
+		b.Fatalf("got %d selected pods, want %d", tsp.Status.SelectedPods, want)
+	}
+}
+
+// BenchmarkNamespaceLabelChange maps a namespace moving between two policies
+// to the policies to requeue.
+func BenchmarkNamespaceLabelChange(b *testing.B) {
+	r := newScaleReconciler(b)
+	ctx := context.Background()
+	for i := range scalePolicies {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: fmt.Sprintf("policy-%d", i)}}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	old := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-0", Labels: map[string]string{"team": "team-0"}}}
+	updated := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-0", Labels: map[string]string{"team": "team-1"}}}
+	handler := r.namespaceHandler()
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	b.ResetTimer()
+	for range b.N {
+		handler.Update(ctx, event.UpdateEvent{ObjectOld: old, ObjectNew: updated}, queue)
+		if queue.Len() != 2 {
+			b.Fatalf("got %d policies queued, want 2", queue.Len())
+		}
+		for queue.Len() > 0 {
+			item, _ := queue.Get()
+			queue.Done(item)
+			queue.Forget(item)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/metrics"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// SLOCheckInterval is how often policies with an SLO are re-evaluated, so
+// that pods which stop reporting are noticed.
+const SLOCheckInterval = time.Minute
+
+// maxListedPods caps the pods named in the ClockSkewExceeded condition.
+const maxListedPods = 5
+
+// sloReport collects the SLO state of the injected pods of a policy.
+type sloReport struct {
+	slo        *syncv1beta1.ClockSkewSLO
+	now        time.Time
+	pods       int
+	outOfSLO   []string
+	violations []string
+}
+
+// add records the offset the pod reports and checks it against the SLO.
+func (s *sloReport) add(policyName string, pod *corev1.Pod) {
+	c := policy.CheckSLO(s.slo, pod, s.now)
+	if c.Reported {
+		metrics.OffsetSeconds.WithLabelValues(policyName, pod.Namespace, pod.Name).Set(c.Offset.Seconds())
+	}
+	if s.slo == nil {
+		return
+	}
+	s.pods++
+	if c.Violation != "" {
+		name := pod.Namespace + "/" + pod.Name
+		s.outOfSLO = append(s.outOfSLO, name)
+		s.violations = append(s.violations, name+": "+c.Violation)
+	}
+}
+
+// setSLOStatus records the report in the policy status and metrics, and
+// emits an Event when the policy enters or leaves violation.
+func (r *TimeSyncPolicyReconciler) setSLOStatus(tsp *syncv1beta1.TimeSyncPolicy, report *sloReport) {
+	wasExceeded := meta.IsStatusConditionTrue(tsp.Status.Conditions, syncv1beta1.ConditionClockSkewExceeded)
+	tsp.Status.OutOfSLOPods = len(report.outOfSLO)
+
+	cond := metav1.Condition{
+		Type:               syncv1beta1.ConditionClockSkewExceeded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: tsp.Generation,
+	}
+	switch {
+	case tsp.Spec.SLO == nil:
+		cond.Reason = "NoSLO"
+		cond.Message = "The policy does not define an SLO"
+	case len(report.outOfSLO) == 0:
+		cond.Reason = "WithinSLO"
+		cond.Message = fmt.Sprintf("All %d injected pods are within the SLO", report.pods)
+	default:
+		// Only name the pods: the details change with every check and
+		// would make each reconcile rewrite the status.
+		listed := report.outOfSLO
+		if len(listed) > maxListedPods {
+			listed = append(listed[:maxListedPods:maxListedPods], "...")
+		}
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "SLOViolated"
+		cond.Message = fmt.Sprintf("%d of %d injected pods are outside the SLO: %s",
+			len(report.outOfSLO), report.pods, strings.Join(listed, ", "))
+	}
+	meta.SetStatusCondition(&tsp.Status.Conditions, cond)
+
+	if tsp.Spec.SLO != nil {
+		compliance := 1.0
+		if report.pods > 0 {
+			compliance = float64(report.pods-len(report.outOfSLO)) / float64(report.pods)
+		}
+		metrics.OutOfSLOPods.WithLabelValues(tsp.Name).Set(float64(len(report.outOfSLO)))
+		metrics.SLOCompliance.WithLabelValues(tsp.Name).Set(compliance)
+	}
+
+	if r.Recorder == nil {
+		return
+	}
+	switch exceeded := cond.Status == metav1.ConditionTrue; {
+	case exceeded && !wasExceeded:
+		violations := report.violations
+		if len(violations) > maxListedPods {
+			violations = append(violations[:maxListedPods:maxListedPods],
+				fmt.Sprintf("and %d more", len(report.violations)-maxListedPods))
+		}
+		r.Recorder.Event(tsp, corev1.EventTypeWarning, "ClockSkewExceeded", strings.Join(violations, "; "))
+	case !exceeded && wasExceeded:
+		r.Recorder.Event(tsp, corev1.EventTypeNormal, "ClockSkewRecovered", cond.Message)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/tracing"
+)
+
+// ExceptionExpiryWarning is how long before a TimeSyncException expires that
+// its owner is warned.
+const ExceptionExpiryWarning = 24 * time.Hour
+
+// TimeSyncExceptionReconciler reports whether each TimeSyncException still
+// applies and warns before it expires.
+type TimeSyncExceptionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder emits Events when an exception is about to expire and when
+	// it expires.
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=sync.example.com,resources=timesyncexceptions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sync.example.com,resources=timesyncexceptions/status,verbs=get;update;patch
+
+// Reconcile sets the Active condition of an exception and requeues it for
+// its next transition.
+func (r *TimeSyncExceptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TimeSyncException.Reconcile",
+		trace.WithAttributes(attribute.String("exception", req.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	var exception syncv1beta1.TimeSyncException
+	if err := r.Get(ctx, req.NamespacedName, &exception); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	original := exception.Status.DeepCopy()
+	var previous string
+	if c := meta.FindStatusCondition(exception.Status.Conditions, syncv1beta1.ConditionActive); c != nil {
+		previous = c.Reason
+	}
+	cond, requeueAfter := exceptionCondition(&exception, time.Now())
+	meta.SetStatusCondition(&exception.Status.Conditions, cond)
+	if equality.Semantic.DeepEqual(&exception.Status, original) {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	if err := r.Status().Update(ctx, &exception); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logf.FromContext(ctx).Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	if previous != cond.Reason {
+		logf.FromContext(ctx).Info("TimeSyncException changed state", "reason", cond.Reason)
+		switch cond.Reason {
+		case "ExpiringSoon":
+			r.event(&exception, corev1.EventTypeWarning, cond.Reason, cond.Message)
+		case "Expired":
+			r.event(&exception, corev1.EventTypeNormal, cond.Reason, cond.Message)
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// exceptionCondition derives the Active condition of the exception at now,
+// along with how long until it next changes.
+func exceptionCondition(e *syncv1beta1.TimeSyncException, now time.Time) (metav1.Condition, time.Duration) {
+	cond := metav1.Condition{
+		Type:               syncv1beta1.ConditionActive,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: e.Generation,
+	}
+	expiresAt := e.Spec.ExpiresAt.UTC().Format(time.RFC3339)
+	left := e.Spec.ExpiresAt.Sub(now)
+	switch {
+	case !policy.ExceptionActive(e, now):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "Expired"
+		cond.Message = fmt.Sprintf("The exception owned by %s expired at %s; its pods are injected again", e.Spec.Owner, expiresAt)
+		return cond, 0
+	case left <= ExceptionExpiryWarning:
+		cond.Reason = "ExpiringSoon"
+		cond.Message = fmt.Sprintf("The exception owned by %s expires at %s", e.Spec.Owner, expiresAt)
+		return cond, left
+	default:
+		cond.Reason = "Active"
+		cond.Message = fmt.Sprintf("The exception applies until %s", expiresAt)
+		return cond, left - ExceptionExpiryWarning
+	}
+}
+
+func (r *TimeSyncExceptionReconciler) event(e *syncv1beta1.TimeSyncException, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(e, eventType, reason, message)
+	}
+}
+
+// activeExceptions lists the unexpired exceptions that cover any of the
+// namespaces for the policy, along with how long until the first of them
+// expires.
+func activeExceptions(
+	exceptions []syncv1beta1.TimeSyncException,
+	policyName string,
+	namespaces []*corev1.Namespace,
+	now time.Time,
+) ([]string, time.Duration) {
+	var active []string
+	var next time.Duration
+	for i := range exceptions {
+		e := &exceptions[i]
+		if !policy.ExceptionActive(e, now) {
+			continue
+		}
+		for _, ns := range namespaces {
+			if covered, err := policy.ExceptionCoversNamespace(e, policyName, ns); err != nil || !covered {
+				continue
+			}
+			active = append(active, e.Name)
+			if left := e.Spec.ExpiresAt.Sub(now); next == 0 || left < next {
+				next = left
+			}
+			break
+		}
+	}
+	sort.Strings(active)
+	return active, next
+}
+
+// SetupWithManager wires the controller to TimeSyncExceptions.
+func (r *TimeSyncExceptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&syncv1beta1.TimeSyncException{}).
+		Named("timesyncexception").
+		Complete(r)
+}
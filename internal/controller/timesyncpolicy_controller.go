@@ -18,33 +18,94 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/metrics"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/sidecar"
+	"github.com/Septimus4/timesync-operator/internal/tracing"
 )
 
 // TimeSyncPolicyReconciler reconciles a TimeSyncPolicy object
 type TimeSyncPolicyReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Images pins sidecar images to digests for policies that ask for it.
+	Images ImageResolver
+	// ImageRefreshInterval is how often pinned images are re-resolved;
+	// DefaultImageRefreshInterval when zero.
+	ImageRefreshInterval time.Duration
+
+	// APIReader reads the source Secrets of image pull secrets, which the
+	// cache does not hold; the Client when nil.
+	APIReader client.Reader
+
+	// Recorder emits Events when a policy enters or leaves SLO violation.
+	Recorder record.EventRecorder
+	// MonitoringNamespace is where the PrometheusRules and PodMonitors of
+	// policies are created; they are not generated when it is empty.
+	MonitoringNamespace string
+	// RevisionNamespace is where the ControllerRevisions of policies are
+	// stored; revision history and rollback are off when it is empty.
+	RevisionNamespace string
+
+	// MaxConcurrentReconciles is how many policies are reconciled in
+	// parallel; one when zero.
+	MaxConcurrentReconciles int
+	// RateLimiter paces requeues of failed policies; the controller-runtime
+	// default when nil.
+	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+
+	index namespaceIndex
+}
+
+// NewRateLimiter returns the requeue rate limiter of the controller-runtime
+// default with tunable parameters: the slower of a per-item exponential
+// backoff from baseDelay to maxDelay and an overall token bucket of qps with
+// burst.
+func NewRateLimiter(baseDelay, maxDelay time.Duration, qps float64, burst int) workqueue.TypedRateLimiter[reconcile.Request] {
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
 }
 
 // +kubebuilder:rbac:groups=sync.example.com,resources=timesyncpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sync.example.com,resources=timesyncpolicies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sync.example.com,resources=timesyncpolicies/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sync.example.com,resources=clockadjustmentallowlists,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -54,88 +115,365 @@ type TimeSyncPolicyReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
-func (r *TimeSyncPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *TimeSyncPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TimeSyncPolicy.Reconcile",
+		trace.WithAttributes(attribute.String("policy", req.Name)))
+	defer func() { tracing.End(span, err) }()
 	log := logf.FromContext(ctx)
 
-	var policy syncv1alpha1.TimeSyncPolicy
-	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+	var tsp syncv1beta1.TimeSyncPolicy
+	if err := r.Get(ctx, req.NamespacedName, &tsp); err != nil {
 		if apierrors.IsNotFound(err) {
+			r.index.set(req.Name, nil)
+			metrics.ForgetPolicy(req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Reconciling TimeSyncPolicy", "name", policy.Name)
+	log.Info("Reconciling TimeSyncPolicy", "name", tsp.Name)
+	// The restored spec is reconciled on the update it triggers.
+	if tsp.Spec.RollbackTo != nil {
+		return ctrl.Result{}, r.rollback(ctx, &tsp)
+	}
+	original := tsp.Status.DeepCopy()
+	tsp.Status.ObservedGeneration = tsp.Generation
 
+	matcher, err := policy.Compile(&tsp)
+	if err != nil {
+		reason := "InvalidSelector"
+		var conditionErr *policy.MatchConditionError
+		if errors.As(err, &conditionErr) {
+			reason = "InvalidMatchCondition"
+		}
+		log.Error(err, "Invalid policy", "reason", reason)
+		meta.SetStatusCondition(&tsp.Status.Conditions, metav1.Condition{
+			Type:               syncv1beta1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            err.Error(),
+			ObservedGeneration: tsp.Generation,
+		})
+		return ctrl.Result{}, r.updateStatus(ctx, &tsp, original)
+	}
+
+	// Let the API server (or the cache) filter namespaces and pods by label
+	// rather than listing the whole cluster for every policy.
 	var namespaces corev1.NamespaceList
-	if err := r.List(ctx, &namespaces); err != nil {
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: matcher.NamespaceSelector()}); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
-	if err != nil {
-		log.Error(err, "Invalid namespaceSelector")
-		return ctrl.Result{}, nil
+	var allowlists syncv1beta1.ClockAdjustmentAllowlistList
+	if policy.AdjustsClock(tsp.Spec.ClockAdjustment) {
+		if err := r.List(ctx, &allowlists); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
-	matchCount := 0
-	for _, ns := range namespaces.Items {
-		if selector.Matches(labels.Set(ns.Labels)) {
-			matchCount++
-		}
+	var exceptions syncv1beta1.TimeSyncExceptionList
+	if err := r.List(ctx, &exceptions); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// Optional: update .status with the match count
-	if policy.Status.MatchedNamespaces != matchCount {
-		policy.Status.MatchedNamespaces = matchCount
-		if err := r.Status().Update(ctx, &policy); err != nil {
-			log.Error(err, "Failed to update status")
+	matchCount, selectedCount, auditedCount := 0, 0, 0
+	var unpermitted, matched []string
+	var matchedNamespaces []*corev1.Namespace
+	revisionPods := map[string]int{}
+	metrics.ForgetPolicy(tsp.Name)
+	slo := &sloReport{slo: tsp.Spec.SLO, now: time.Now()}
+	for i := range namespaces.Items {
+		// A namespace being deleted is already lost to the policy.
+		if !namespaces.Items[i].DeletionTimestamp.IsZero() || !matcher.MatchesNamespace(&namespaces.Items[i]) {
+			continue
+		}
+		matchCount++
+		matched = append(matched, namespaces.Items[i].Name)
+		matchedNamespaces = append(matchedNamespaces, &namespaces.Items[i])
+		if policy.AdjustsClock(tsp.Spec.ClockAdjustment) &&
+			!policy.ClockAdjustmentPermitted(allowlists.Items, &namespaces.Items[i]) {
+			unpermitted = append(unpermitted, namespaces.Items[i].Name)
+		}
+
+		var pods corev1.PodList
+		if err := r.List(ctx, &pods, client.InNamespace(namespaces.Items[i].Name),
+			client.MatchingLabelsSelector{Selector: matcher.PodSelector()}); err != nil {
 			return ctrl.Result{}, err
 		}
+		for j := range pods.Items {
+			if matcher.MatchesPod(&pods.Items[j]) {
+				selectedCount++
+				if pods.Items[j].Annotations[policy.AuditAnnotation] == tsp.Name {
+					auditedCount++
+				}
+				if pods.Items[j].Labels[sidecar.InjectedByLabel] == tsp.Name {
+					revisionPods[pods.Items[j].Labels[sidecar.RevisionLabel]]++
+				}
+				if policy.HasSidecar(&pods.Items[j]) {
+					slo.add(tsp.Name, &pods.Items[j])
+				}
+			}
+		}
+	}
+
+	r.index.set(tsp.Name, matched)
+	tsp.Status.MatchedNamespaces = matchCount
+	tsp.Status.SelectedPods = selectedCount
+	tsp.Status.AuditedPods = auditedCount
+	span.SetAttributes(
+		attribute.Int("matched_namespaces", matchCount),
+		attribute.Int("selected_pods", selectedCount),
+		attribute.Int("audited_pods", auditedCount),
+		attribute.Int("out_of_slo_pods", len(slo.outOfSLO)),
+	)
+	sort.Strings(unpermitted)
+	tsp.Status.UnpermittedNamespaces = unpermitted
+	meta.SetStatusCondition(&tsp.Status.Conditions, clockAdjustmentCondition(&tsp, unpermitted))
+	r.setSLOStatus(&tsp, slo)
+	active, expiry := activeExceptions(exceptions.Items, tsp.Name, matchedNamespaces, slo.now)
+	tsp.Status.ActiveExceptions = active
+	// Spec changes and rollout steps wait for an active window, and the
+	// controller wakes up when the next one opens.
+	allowed, requeueAfter := applySpec(&tsp, slo.now)
+	revisionsErr := r.syncRevisions(ctx, &tsp, revisionPods)
+	if revisionsErr != nil {
+		log.Error(revisionsErr, "Failed to sync policy revisions")
+	}
+	waits := []time.Duration{r.resolveImage(ctx, &tsp), expiry}
+	if allowed {
+		waits = append(waits, r.advanceRollout(&tsp, slo.now))
+	}
+	for _, next := range waits {
+		if next > 0 && (requeueAfter == 0 || requeueAfter > next) {
+			requeueAfter = next
+		}
+	}
+	if tsp.Spec.SLO != nil && (requeueAfter == 0 || requeueAfter > SLOCheckInterval) {
+		requeueAfter = SLOCheckInterval
+	}
+	if len(tsp.Spec.ImagePullSecrets) > 0 && (requeueAfter == 0 || requeueAfter > PullSecretSyncInterval) {
+		requeueAfter = PullSecretSyncInterval
 	}
 
-	log.Info("TimeSyncPolicy reconciled", "matchedNamespaces", matchCount)
-	return ctrl.Result{}, nil
+	// Secrets are only needed where the sidecar is injected.
+	if !tsp.Spec.Enable {
+		matched = nil
+	}
+	secretsErr := r.syncPullSecrets(ctx, &tsp, matched)
+	setPullSecretsCondition(&tsp, secretsErr)
+	admissionErr := r.syncAdmissionPolicy(ctx, &tsp, matchedNamespaces, exceptions.Items, slo.now)
+	if admissionErr != nil {
+		log.Error(admissionErr, "Failed to sync ValidatingAdmissionPolicy")
+	}
+	meta.SetStatusCondition(&tsp.Status.Conditions, metav1.Condition{
+		Type:               syncv1beta1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reconciled",
+		Message:            "Policy selectors are valid",
+		ObservedGeneration: tsp.Generation,
+	})
+	if err := r.updateStatus(ctx, &tsp, original); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(unpermitted) > 0 {
+		log.Info("Clock adjustment not permitted in matched namespaces", "namespaces", unpermitted)
+	}
+	if err := errors.Join(secretsErr, revisionsErr, admissionErr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.syncMonitoring(ctx, &tsp); err != nil {
+		log.Error(err, "Failed to sync Prometheus Operator resources")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("TimeSyncPolicy reconciled", "matchedNamespaces", matchCount, "selectedPods", selectedCount, "auditedPods", auditedCount)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// clockAdjustmentCondition reports whether every matched namespace may apply
+// the clock adjustment the policy requests.
+func clockAdjustmentCondition(tsp *syncv1beta1.TimeSyncPolicy, unpermitted []string) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               syncv1beta1.ConditionClockAdjustmentPermitted,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: tsp.Generation,
+	}
+	switch {
+	case !policy.AdjustsClock(tsp.Spec.ClockAdjustment):
+		cond.Reason = "NotRequested"
+		cond.Message = "The policy does not adjust the clock"
+	case len(unpermitted) == 0:
+		cond.Reason = "Permitted"
+		cond.Message = "Every matched namespace may adjust the clock"
+	default:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "NotPermitted"
+		cond.Message = fmt.Sprintf("No ClockAdjustmentAllowlist permits namespaces %s; their sidecars will not adjust the clock",
+			strings.Join(unpermitted, ", "))
+	}
+	return cond
 }
 
-// map a *Namespace event to the TimeSyncPolicies it matches
-func (r *TimeSyncPolicyReconciler) mapNamespaceToPolicies(
+// updateStatus writes the policy status if it differs from original.
+func (r *TimeSyncPolicyReconciler) updateStatus(
 	ctx context.Context,
-	obj client.Object,
-) []reconcile.Request {
-	ns, ok := obj.(*corev1.Namespace)
-	if !ok {
+	tsp *syncv1beta1.TimeSyncPolicy,
+	original *syncv1beta1.TimeSyncPolicyStatus,
+) error {
+	if equality.Semantic.DeepEqual(&tsp.Status, original) {
 		return nil
 	}
+	if err := r.Status().Update(ctx, tsp); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update status")
+		return err
+	}
+	return nil
+}
+
+// PodEventDelay is how long pod events are collected before the policies
+// they affect are reconciled. Each reconcile lists the pods of every matched
+// namespace, so reconciling on every event would grow with the square of
+// the pods while a workload scales.
+const PodEventDelay = 10 * time.Second
+
+// podHandler requeues the policies that matched a pod's namespace at their
+// last reconcile, PodEventDelay after the first event. Later events for the
+// same policy are folded into that reconcile by the work queue. The pod
+// selector is deliberately ignored: a label change may have just moved the
+// pod out of a policy, and that policy's counters need refreshing too.
+func (r *TimeSyncPolicyReconciler) podHandler() handler.EventHandler {
+	enqueue := func(q workqueue.TypedRateLimitingInterface[reconcile.Request], obj client.Object) {
+		for _, name := range r.index.lookup(obj.GetNamespace()) {
+			q.AddAfter(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}, PodEventDelay)
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.ObjectNew)
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+		GenericFunc: func(_ context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+	}
+}
 
-	var policies syncv1alpha1.TimeSyncPolicyList
+// map a *ClockAdjustmentAllowlist event to the TimeSyncPolicies that adjust
+// the clock, since any of their namespaces may have gained or lost permission
+func (r *TimeSyncPolicyReconciler) mapAllowlistToPolicies(
+	ctx context.Context,
+	_ client.Object,
+) []reconcile.Request {
+	var policies syncv1beta1.TimeSyncPolicyList
 	if err := r.List(ctx, &policies); err != nil {
 		return nil
 	}
 
 	var reqs []reconcile.Request
-	for _, policy := range policies.Items {
-		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
-		if err != nil {
-			continue
-		}
-		if selector.Matches(labels.Set(ns.Labels)) {
+	for i := range policies.Items {
+		if policy.AdjustsClock(policies.Items[i].Spec.ClockAdjustment) {
 			reqs = append(reqs, reconcile.Request{
-				NamespacedName: types.NamespacedName{Name: policy.Name},
+				NamespacedName: types.NamespacedName{Name: policies.Items[i].Name},
 			})
 		}
 	}
 	return reqs
 }
 
+// map a *TimeSyncException event to the TimeSyncPolicies it names, or to
+// every policy when it names none
+func (r *TimeSyncPolicyReconciler) mapExceptionToPolicies(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	exception, ok := obj.(*syncv1beta1.TimeSyncException)
+	if !ok {
+		return nil
+	}
+	names := exception.Spec.Policies
+	if len(names) == 0 {
+		var policies syncv1beta1.TimeSyncPolicyList
+		if err := r.List(ctx, &policies); err != nil {
+			return nil
+		}
+		for i := range policies.Items {
+			names = append(names, policies.Items[i].Name)
+		}
+	}
+
+	reqs := make([]reconcile.Request, 0, len(names))
+	for _, name := range names {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return reqs
+}
+
+// map a *NamespaceTimeSyncPolicy event to the TimeSyncPolicies that matched
+// its namespace at their last reconcile, whose admission policies exempt
+// namespaces that opt out
+func (r *TimeSyncPolicyReconciler) mapOverrideToPolicies(
+	_ context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	names := r.index.lookup(obj.GetNamespace())
+	reqs := make([]reconcile.Request, 0, len(names))
+	for _, name := range names {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return reqs
+}
+
 // SetupWithManager wires the controller
 func (r *TimeSyncPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&syncv1alpha1.TimeSyncPolicy{}).
+		For(&syncv1beta1.TimeSyncPolicy{}).
 		Watches(
 			&corev1.Namespace{},
-			handler.TypedEnqueueRequestsFromMapFunc[client.Object](r.mapNamespaceToPolicies),
+			r.namespaceHandler(),
+			// Policies only see namespace labels and deletion; annotation
+			// and status churn must not fan out to every policy.
+			builder.WithPredicates(predicate.Or[client.Object](
+				predicate.LabelChangedPredicate{},
+				deletionStartedPredicate(),
+			)),
+		).
+		Watches(
+			&corev1.Pod{},
+			r.podHandler(),
+			// Sidecars report their clock state through annotations.
+			builder.WithPredicates(predicate.Or[client.Object](
+				predicate.LabelChangedPredicate{},
+				predicate.AnnotationChangedPredicate{},
+			)),
+		).
+		Watches(
+			&syncv1beta1.ClockAdjustmentAllowlist{},
+			handler.TypedEnqueueRequestsFromMapFunc[client.Object](r.mapAllowlistToPolicies),
+		).
+		Watches(
+			&syncv1beta1.TimeSyncException{},
+			handler.TypedEnqueueRequestsFromMapFunc[client.Object](r.mapExceptionToPolicies),
+		).
+		Watches(
+			&syncv1alpha1.NamespaceTimeSyncPolicy{},
+			handler.TypedEnqueueRequestsFromMapFunc[client.Object](r.mapOverrideToPolicies),
+		).
+		// The manager caches only the copies (see ManagedSecrets), so edits
+		// to them are reverted; sources are re-read periodically.
+		Watches(
+			&corev1.Secret{},
+			handler.TypedEnqueueRequestsFromMapFunc[client.Object](r.mapSecretToPolicies),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             r.RateLimiter,
+		}).
 		Complete(r)
 }
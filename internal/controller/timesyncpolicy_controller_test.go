@@ -18,18 +18,62 @@ package controller
 
 import (
 	"context"
+	"crypto"
+	"fmt"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/registry"
 )
 
+// fakeImages is an in-memory ImageResolver.
+type fakeImages struct {
+	digests map[string]string
+	signed  map[string]bool
+}
+
+func (f *fakeImages) Resolve(_ context.Context, image string, _ registry.Credentials) (string, error) {
+	if d, ok := f.digests[image]; ok {
+		return d, nil
+	}
+	return "", fmt.Errorf("manifest unknown")
+}
+
+func (f *fakeImages) Verify(_ context.Context, _ string, digest string, _ crypto.PublicKey, _ registry.Credentials) error {
+	if f.signed[digest] {
+		return nil
+	}
+	return fmt.Errorf("no valid signature")
+}
+
+// testPublicKey is an ECDSA P-256 public key; fakeImages does not check it.
+const testPublicKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE3N6h2EjwjYZmuQtdV9AQCgntGybk
+UU4J74KpJX8z/JZlpczO96ohZs2Va5db3ouZ77kqq7xDO8GK9WlaBI6zdQ==
+-----END PUBLIC KEY-----`
+
 var _ = Describe("TimeSyncPolicy Controller", func() {
 	Context("When reconciling a resource", func() {
 		const resourceName = "test-resource"
@@ -40,13 +84,13 @@ var _ = Describe("TimeSyncPolicy Controller", func() {
 			Name:      resourceName,
 			Namespace: "default",
 		}
-		timesyncpolicy := &syncv1alpha1.TimeSyncPolicy{}
+		timesyncpolicy := &syncv1beta1.TimeSyncPolicy{}
 
 		BeforeEach(func() {
 			By("creating the custom resource for the Kind TimeSyncPolicy")
 			err := k8sClient.Get(ctx, typeNamespacedName, timesyncpolicy)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &syncv1alpha1.TimeSyncPolicy{
+				resource := &syncv1beta1.TimeSyncPolicy{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
@@ -58,7 +102,7 @@ var _ = Describe("TimeSyncPolicy Controller", func() {
 
 		AfterEach(func() {
 			By("Cleanup the specific resource instance TimeSyncPolicy")
-			resource := &syncv1alpha1.TimeSyncPolicy{}
+			resource := &syncv1beta1.TimeSyncPolicy{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			if err != nil {
 				if errors.IsNotFound(err) {
@@ -86,4 +130,985 @@ var _ = Describe("TimeSyncPolicy Controller", func() {
 			Expect(timesyncpolicy.Status.MatchedNamespaces).To(BeNumerically(">=", 0))
 		})
 	})
+
+	Context("When a policy has a pod selector", func() {
+		ctx := context.Background()
+
+		It("should count only the selected pods", func() {
+			By("creating a namespace with a database and a web pod")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "pod-selector-ns",
+				Labels: map[string]string{"env": "pod-selector"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			for name, component := range map[string]string{"db-0": "database", "web-0": "web"} {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: ns.Name,
+						Labels:    map[string]string{"app.kubernetes.io/component": component},
+					},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:latest"}}},
+				}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			}
+
+			By("creating a policy that selects database pods")
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "database-only"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "pod-selector"}},
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app.kubernetes.io/component": "database"},
+					},
+					Enable:   true,
+					Template: syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the status counters")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			Expect(resource.Status.MatchedNamespaces).To(Equal(1))
+			Expect(resource.Status.SelectedPods).To(Equal(1))
+		})
+	})
+
+	Context("When a policy is in audit mode", func() {
+		ctx := context.Background()
+
+		It("should count the pods it would have injected", func() {
+			By("creating a namespace with an audited and an unaudited pod")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "audit",
+				Labels: map[string]string{"env": "audit"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			for name, annotations := range map[string]map[string]string{
+				"audited":   {policy.AuditAnnotation: "audit-policy"},
+				"unaudited": nil,
+			} {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "audit", Annotations: annotations},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:latest"}}},
+				}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			}
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "audit-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "audit"}},
+					Enable:            true,
+					Mode:              syncv1beta1.PolicyModeAudit,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			Expect(resource.Status.SelectedPods).To(Equal(2))
+			Expect(resource.Status.AuditedPods).To(Equal(1))
+		})
+	})
+
+	Context("When a policy rolls out in steps", func() {
+		It("should advance one step at a time and hold while the SLO is violated", func() {
+			recorder := record.NewFakeRecorder(10)
+			r := &TimeSyncPolicyReconciler{Recorder: recorder}
+			tsp := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "rollout-policy", Generation: 1},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					Rollout: &syncv1beta1.Rollout{
+						Percentage: 10,
+						Steps: []syncv1beta1.RolloutStep{
+							{Percentage: 50, After: metav1.Duration{Duration: time.Hour}},
+							{Percentage: 100, After: metav1.Duration{Duration: time.Hour}},
+						},
+					},
+				},
+			}
+			start := time.Now().Truncate(time.Second)
+
+			By("starting at the rollout percentage")
+			Expect(r.advanceRollout(tsp, start)).To(Equal(time.Hour))
+			Expect(tsp.Status.Rollout.Percentage).To(BeEquivalentTo(10))
+			Expect(policy.RolloutPercentage(tsp)).To(BeEquivalentTo(10))
+
+			By("advancing a single step even when both are overdue")
+			Expect(r.advanceRollout(tsp, start.Add(3*time.Hour))).To(Equal(time.Hour))
+			Expect(tsp.Status.Rollout.Step).To(BeEquivalentTo(1))
+			Expect(policy.RolloutPercentage(tsp)).To(BeEquivalentTo(50))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RolloutAdvanced")))
+
+			By("holding while pods are outside the SLO")
+			meta.SetStatusCondition(&tsp.Status.Conditions, metav1.Condition{
+				Type: syncv1beta1.ConditionClockSkewExceeded, Status: metav1.ConditionTrue, Reason: "SLOViolated",
+			})
+			Expect(r.advanceRollout(tsp, start.Add(5*time.Hour))).To(BeZero())
+			Expect(tsp.Status.Rollout.Paused).To(BeTrue())
+			Expect(tsp.Status.Rollout.Percentage).To(BeEquivalentTo(50))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RolloutPaused")))
+
+			By("restarting the step once the SLO recovers")
+			meta.SetStatusCondition(&tsp.Status.Conditions, metav1.Condition{
+				Type: syncv1beta1.ConditionClockSkewExceeded, Status: metav1.ConditionFalse, Reason: "WithinSLO",
+			})
+			Expect(r.advanceRollout(tsp, start.Add(6*time.Hour))).To(Equal(time.Hour))
+			Expect(tsp.Status.Rollout.Paused).To(BeFalse())
+			Expect(recorder.Events).To(Receive(ContainSubstring("RolloutResumed")))
+			Expect(r.advanceRollout(tsp, start.Add(7*time.Hour))).To(BeZero())
+			Expect(policy.RolloutPercentage(tsp)).To(BeEquivalentTo(100))
+
+			By("carrying on when the spec changes but the sidecar does not")
+			tsp.Spec.Priority = 5
+			Expect(policy.RolloutPercentage(tsp)).To(BeEquivalentTo(100))
+			Expect(r.advanceRollout(tsp, start.Add(8*time.Hour))).To(BeZero())
+			Expect(tsp.Status.Rollout.Step).To(BeEquivalentTo(2))
+
+			By("restarting the rollout when the image changes")
+			tsp.Spec.Template.Image = "timesync:v2"
+			Expect(policy.RolloutPercentage(tsp)).To(BeEquivalentTo(10))
+			Expect(r.advanceRollout(tsp, start.Add(8*time.Hour))).To(Equal(time.Hour))
+			Expect(tsp.Status.Rollout.Step).To(BeZero())
+		})
+	})
+
+	Context("When changes are held back", func() {
+		ctx := context.Background()
+
+		It("should keep the applied spec while suspended or outside every window", func() {
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "frozen-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "frozen"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:v1"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: resource.Name}}
+			reconcileAndGet := func() ctrl.Result {
+				result, err := controllerReconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Get(ctx, request.NamespacedName, resource)).To(Succeed())
+				return result
+			}
+
+			By("applying the spec while changes are allowed")
+			reconcileAndGet()
+			Expect(resource.Status.AppliedSpec.Template.Image).To(Equal("timesync:v1"))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, syncv1beta1.ConditionSpecApplied)).To(BeTrue())
+
+			By("holding a change back while suspended")
+			resource.Spec.Suspend = true
+			resource.Spec.Template.Image = "timesync:v2"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			reconcileAndGet()
+			Expect(resource.Status.AppliedSpec.Template.Image).To(Equal("timesync:v1"))
+			cond := meta.FindStatusCondition(resource.Status.Conditions, syncv1beta1.ConditionSpecApplied)
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("Suspended"))
+
+			By("waking up when the next window opens")
+			resource.Spec.Suspend = false
+			resource.Spec.ActiveWindows = []syncv1beta1.ActiveWindow{{
+				Schedule: "0 0 1 1 *",
+				Duration: metav1.Duration{Duration: time.Minute},
+			}}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			result := reconcileAndGet()
+			Expect(resource.Status.AppliedSpec.Template.Image).To(Equal("timesync:v1"))
+			Expect(resource.Status.NextWindow).NotTo(BeNil())
+			Expect(resource.Status.NextWindow.Month()).To(Equal(time.January))
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Until(resource.Status.NextWindow.Time), time.Minute))
+			cond = meta.FindStatusCondition(resource.Status.Conditions, syncv1beta1.ConditionSpecApplied)
+			Expect(cond.Reason).To(Equal("OutsideWindow"))
+		})
+	})
+
+	Context("When a match condition does not compile", func() {
+		ctx := context.Background()
+
+		It("should report it in the Ready condition", func() {
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "cel-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "cel"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+					MatchConditions: []syncv1beta1.MatchCondition{{
+						Name:       "not-a-job",
+						Expression: "object.metadata.ownerReferences.exists(r, r.kind ==",
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			Expect(resource.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", syncv1beta1.ConditionReady),
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "InvalidMatchCondition"),
+				HaveField("Message", ContainSubstring(`invalid matchCondition "not-a-job"`)),
+			)))
+		})
+	})
+
+	Context("When a policy changes", func() {
+		ctx := context.Background()
+
+		It("should store revisions, count their pods and roll back", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "revision-ns",
+				Labels: map[string]string{"env": "revision"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "revision-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "revision"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:v1"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				Recorder:          recorder,
+				RevisionNamespace: metav1.NamespaceDefault,
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: resource.Name}}
+			reconcileAndGet := func() {
+				_, err := controllerReconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Get(ctx, request.NamespacedName, resource)).To(Succeed())
+			}
+
+			By("storing the first revision")
+			reconcileAndGet()
+			first := resource.Status.CurrentRevision
+			Expect(first).To(Equal(policy.RevisionHash(&resource.Spec)))
+			Expect(resource.Status.Revisions).To(Equal([]syncv1beta1.RevisionStatus{{Revision: 1, Hash: first}}))
+
+			By("counting the pods injected with it")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "revision-pod",
+					Namespace: ns.Name,
+					Labels: map[string]string{
+						"sync.example.com/injected-by":     resource.Name,
+						"sync.example.com/policy-revision": first,
+					},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:latest"}}},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, pod)
+
+			By("storing a new revision when the spec changes")
+			resource.Spec.Template.Image = "timesync:v2"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			reconcileAndGet()
+			second := resource.Status.CurrentRevision
+			Expect(second).NotTo(Equal(first))
+			Expect(resource.Status.Revisions).To(Equal([]syncv1beta1.RevisionStatus{
+				{Revision: 2, Hash: second},
+				{Revision: 1, Hash: first, Pods: 1},
+			}))
+
+			By("restoring the first revision")
+			resource.Spec.RollbackTo = ptr.To[int64](1)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			reconcileAndGet()
+			Expect(resource.Spec.RollbackTo).To(BeNil())
+			Expect(resource.Spec.Template.Image).To(Equal("timesync:v1"))
+			Expect(recorder.Events).To(Receive(Equal("Normal RolledBack Rolled back to revision 1")))
+
+			By("renumbering the restored revision")
+			reconcileAndGet()
+			Expect(resource.Status.CurrentRevision).To(Equal(first))
+			Expect(resource.Status.Revisions).To(Equal([]syncv1beta1.RevisionStatus{
+				{Revision: 3, Hash: first, Pods: 1},
+				{Revision: 2, Hash: second},
+			}))
+		})
+	})
+
+	Context("When an exception exempts pods", func() {
+		ctx := context.Background()
+
+		It("should warn before the exception expires and list it on the policy", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "exempt-ns",
+				Labels: map[string]string{"env": "exempt"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+			exception := &syncv1beta1.TimeSyncException{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy-ledger"},
+				Spec: syncv1beta1.TimeSyncExceptionSpec{
+					Namespaces: []string{"exempt-ns"},
+					Reason:     "ledger pins its own NTP client",
+					Owner:      "billing-team",
+					ExpiresAt:  metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second)),
+				},
+			}
+			Expect(k8sClient.Create(ctx, exception)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, exception)
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "exempt-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "exempt"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			By("marking the exception as expiring soon")
+			recorder := record.NewFakeRecorder(10)
+			exceptionReconciler := &TimeSyncExceptionReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: exception.Name}}
+			result, err := exceptionReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			Expect(k8sClient.Get(ctx, request.NamespacedName, exception)).To(Succeed())
+			cond := meta.FindStatusCondition(exception.Status.Conditions, syncv1beta1.ConditionActive)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal("ExpiringSoon"))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning ExpiringSoon")))
+
+			By("listing the exception on the policy status")
+			policyReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			result, err = policyReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			Expect(resource.Status.ActiveExceptions).To(Equal([]string{"legacy-ledger"}))
+
+			By("expiring the exception")
+			exception.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			Expect(k8sClient.Update(ctx, exception)).To(Succeed())
+			result, err = exceptionReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(k8sClient.Get(ctx, request.NamespacedName, exception)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(exception.Status.Conditions, syncv1beta1.ConditionActive)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Expired")))
+
+			_, err = policyReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			Expect(resource.Status.ActiveExceptions).To(BeEmpty())
+		})
+	})
+
+	Context("When a policy requires the sidecar", func() {
+		ctx := context.Background()
+
+		It("should keep a ValidatingAdmissionPolicy that enforces it", func() {
+			for _, name := range []string{"require-ns", "require-opted-out"} {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{"env": "require"},
+				}}
+				Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			}
+			override := &syncv1alpha1.NamespaceTimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "opt-out", Namespace: "require-opted-out"},
+				Spec:       syncv1alpha1.NamespaceTimeSyncPolicySpec{Enable: ptr.To(false)},
+			}
+			Expect(k8sClient.Create(ctx, override)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, override)
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "require"}},
+					Enable:            true,
+					Enforcement:       syncv1beta1.EnforcementRequire,
+					AllowedOverrides:  []syncv1beta1.OverridableField{syncv1beta1.OverridableFieldEnable},
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: resource.Name}}
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("generating the policy and binding")
+			key := types.NamespacedName{Name: "timesync-require-policy"}
+			vap := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+			Expect(k8sClient.Get(ctx, key, vap)).To(Succeed())
+			Expect(metav1.IsControlledBy(vap, resource)).To(BeTrue())
+			Expect(vap.Spec.MatchConstraints.NamespaceSelector.MatchLabels).To(HaveKeyWithValue("env", "require"))
+			Expect(vap.Spec.Variables).To(HaveLen(1))
+			Expect(vap.Spec.Variables[0].Expression).To(ContainSubstring(`"require-opted-out"`))
+			Expect(vap.Spec.Variables[0].Expression).NotTo(ContainSubstring(`"require-ns"`))
+			binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+			Expect(k8sClient.Get(ctx, key, binding)).To(Succeed())
+			Expect(binding.Spec.PolicyName).To(Equal(key.Name))
+			Expect(binding.Spec.ValidationActions).To(Equal([]admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}))
+
+			Expect(k8sClient.Get(ctx, request.NamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, syncv1beta1.ConditionAdmissionPolicySynced)).To(BeTrue())
+
+			By("deleting them once the sidecar is optional")
+			resource.Spec.Enforcement = syncv1beta1.EnforcementOptional
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, key, vap))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, key, binding))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, request.NamespacedName, resource)).To(Succeed())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, syncv1beta1.ConditionAdmissionPolicySynced)).To(BeNil())
+		})
+
+		It("should hold the ValidatingAdmissionPolicy back until a signed image is verified", func() {
+			digest := "sha256:" + strings.Repeat("c", 64)
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-signed-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "require-signed"}},
+					Enable:            true,
+					Enforcement:       syncv1beta1.EnforcementRequire,
+					Template:          syncv1beta1.SidecarTemplate{Image: "registry.example.com/timesync:v1"},
+					ImagePolicy:       &syncv1beta1.ImagePolicy{PublicKey: testPublicKey},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			images := &fakeImages{digests: map[string]string{"registry.example.com/timesync:v1": digest}}
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Images: images,
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: resource.Name}}
+			key := types.NamespacedName{Name: "timesync-require-signed-policy"}
+
+			By("not generating it while the signature does not verify")
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, key, &admissionregistrationv1.ValidatingAdmissionPolicy{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, request.NamespacedName, resource)).To(Succeed())
+			cond := meta.FindStatusCondition(resource.Status.Conditions, syncv1beta1.ConditionAdmissionPolicySynced)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("ImageUnverified"))
+
+			By("generating it once the image is verified")
+			images.signed = map[string]bool{digest: true}
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, &admissionregistrationv1.ValidatingAdmissionPolicy{})).To(Succeed())
+			Expect(k8sClient.Get(ctx, request.NamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, syncv1beta1.ConditionAdmissionPolicySynced)).To(BeTrue())
+		})
+	})
+
+	Context("When a policy adjusts the clock", func() {
+		ctx := context.Background()
+
+		It("should report the namespaces no allowlist permits", func() {
+			By("creating an allowed and a denied namespace")
+			for _, name := range []string{"clock-allowed", "clock-denied"} {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{"env": "clock"},
+				}}
+				Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			}
+
+			By("allowing only one of them to adjust the clock")
+			allowlist := &syncv1beta1.ClockAdjustmentAllowlist{
+				ObjectMeta: metav1.ObjectMeta{Name: "clock-allowlist"},
+				Spec:       syncv1beta1.ClockAdjustmentAllowlistSpec{Namespaces: []string{"clock-allowed"}},
+			}
+			Expect(k8sClient.Create(ctx, allowlist)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, allowlist)
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "clock-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "clock"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+					Backend:           syncv1beta1.BackendChrony,
+					ClockAdjustment:   syncv1beta1.ClockAdjustmentSlew,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the status lists the denied namespace")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			Expect(resource.Status.UnpermittedNamespaces).To(Equal([]string{"clock-denied"}))
+			Expect(resource.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", syncv1beta1.ConditionClockAdjustmentPermitted),
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "NotPermitted"),
+			)))
+		})
+	})
+
+	Context("When a policy pins its image", func() {
+		ctx := context.Background()
+		digest := "sha256:" + strings.Repeat("b", 64)
+
+		It("should record the digest and whether it is signed", func() {
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "pinned-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "pinned"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "registry.example.com/timesync:v1"},
+					ImagePolicy:       &syncv1beta1.ImagePolicy{PinDigest: true},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			images := &fakeImages{digests: map[string]string{"registry.example.com/timesync:v1": digest}}
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Images: images,
+			}
+			reconcileOnce := func() ctrl.Result {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: resource.Name},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+				return result
+			}
+
+			By("pinning the tag to its digest")
+			Expect(reconcileOnce().RequeueAfter).To(Equal(DefaultImageRefreshInterval))
+			Expect(resource.Status.ResolvedImage).To(Equal("registry.example.com/timesync:v1@" + digest))
+
+			By("refusing to pin an unsigned digest once a public key is set")
+			resource.Spec.ImagePolicy.PublicKey = testPublicKey
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			reconcileOnce()
+			Expect(resource.Status.ResolvedImage).To(BeEmpty())
+			Expect(resource.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", syncv1beta1.ConditionImageResolved),
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", "VerificationFailed"),
+			)))
+
+			By("pinning the digest once it is signed")
+			images.signed = map[string]bool{digest: true}
+			reconcileOnce()
+			Expect(resource.Status.ResolvedImage).To(Equal("registry.example.com/timesync:v1@" + digest))
+			Expect(resource.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", syncv1beta1.ConditionImageResolved),
+				HaveField("Reason", "Verified"),
+			)))
+		})
+	})
+
+	Context("When a policy has image pull secrets", func() {
+		ctx := context.Background()
+
+		It("should copy them into matched namespaces and keep them in sync", func() {
+			By("creating a source secret and a matched namespace")
+			source := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "timesync-pull", Namespace: "default"},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+			}
+			Expect(k8sClient.Create(ctx, source)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, source)
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "pull-secret-ns",
+				Labels: map[string]string{"env": "pull-secret"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "pull-secret-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "pull-secret"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "registry.example.com/timesync:v1"},
+					ImagePullSecrets:  []syncv1beta1.ImagePullSecret{{Name: "timesync-pull", Namespace: "default"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			reconcileOnce := func() {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: resource.Name},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("copying the secret")
+			reconcileOnce()
+			copied := &corev1.Secret{}
+			copyKey := types.NamespacedName{Name: "timesync-pull", Namespace: ns.Name}
+			Expect(k8sClient.Get(ctx, copyKey, copied)).To(Succeed())
+			Expect(copied.Type).To(Equal(corev1.SecretTypeDockerConfigJson))
+			Expect(copied.Data).To(Equal(source.Data))
+			Expect(copied.Labels).To(HaveKeyWithValue(PolicyLabel, resource.Name))
+			Expect(copied.OwnerReferences).To(ContainElement(HaveField("Name", resource.Name)))
+
+			By("propagating changes of the source")
+			source.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths":{"registry.example.com":{}}}`)
+			Expect(k8sClient.Update(ctx, source)).To(Succeed())
+			reconcileOnce()
+			Expect(k8sClient.Get(ctx, copyKey, copied)).To(Succeed())
+			Expect(copied.Data).To(Equal(source.Data))
+
+			By("deleting the copy once the namespace no longer matches")
+			ns.Labels = map[string]string{"env": "other"}
+			Expect(k8sClient.Update(ctx, ns)).To(Succeed())
+			reconcileOnce()
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, copyKey, copied))).To(BeTrue())
+		})
+
+		It("should refuse to copy secrets that are not registry credentials", func() {
+			source := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "timesync-token", Namespace: "default"},
+				Type:       corev1.SecretTypeOpaque,
+				Data:       map[string][]byte{"token": []byte("secret")},
+			}
+			Expect(k8sClient.Create(ctx, source)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, source)
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "opaque-secret-ns",
+				Labels: map[string]string{"env": "opaque-secret"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "opaque-secret-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "opaque-secret"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "registry.example.com/timesync:v1"},
+					ImagePullSecrets:  []syncv1beta1.ImagePullSecret{{Name: "timesync-token", Namespace: "default"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).To(MatchError(ContainSubstring(`has type "Opaque"`)))
+			copyKey := types.NamespacedName{Name: "timesync-token", Namespace: ns.Name}
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, copyKey, &corev1.Secret{}))).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			cond := meta.FindStatusCondition(resource.Status.Conditions, syncv1beta1.ConditionImagePullSecretsSynced)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("UnsupportedSecretType"))
+		})
+	})
+
+	Context("When a policy has a clock skew SLO", func() {
+		ctx := context.Background()
+
+		It("should report pods whose offset exceeds it", func() {
+			By("creating a namespace with a skewed and a synchronized pod")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "slo",
+				Labels: map[string]string{"env": "slo"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			syncedAt := time.Now().UTC().Format(time.RFC3339)
+			for name, offset := range map[string]string{"skewed": "0.5", "synced": "0.001"} {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: ns.Name,
+						Annotations: map[string]string{
+							policy.OffsetAnnotation:   offset,
+							policy.SyncedAtAnnotation: syncedAt,
+						},
+					},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{
+						{Name: "app", Image: "app:latest"},
+						{Name: policy.SidecarName, Image: "timesync:latest"},
+					}},
+				}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			}
+
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "slo-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "slo"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+					SLO: &syncv1beta1.ClockSkewSLO{
+						MaxOffset:           &metav1.Duration{Duration: 100 * time.Millisecond},
+						MaxUnsyncedDuration: &metav1.Duration{Duration: time.Hour},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: resource.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(SLOCheckInterval))
+
+			By("verifying the condition, the counter and the Event")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resource.Name}, resource)).To(Succeed())
+			Expect(resource.Status.OutOfSLOPods).To(Equal(1))
+			cond := meta.FindStatusCondition(resource.Status.Conditions, syncv1beta1.ConditionClockSkewExceeded)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(ContainSubstring("slo/skewed"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ClockSkewExceeded")))
+		})
+	})
+
+	Context("When a namespace stops matching a policy", func() {
+		ctx := context.Background()
+
+		It("should requeue and recount the policy on label removal and namespace deletion", func() {
+			By("creating two matching namespaces and a policy")
+			for _, name := range []string{"lose-label", "lose-delete"} {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{"env": "lose"},
+				}}
+				Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			}
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "lose-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "lose"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: resource.Name}}
+			matchedNamespaces := func() int {
+				_, err := controllerReconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Get(ctx, request.NamespacedName, resource)).To(Succeed())
+				return resource.Status.MatchedNamespaces
+			}
+			Expect(matchedNamespaces()).To(Equal(2))
+
+			handler := controllerReconciler.namespaceHandler()
+			queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			DeferCleanup(queue.ShutDown)
+			expectQueued := func() {
+				Expect(queue.Len()).To(Equal(1))
+				item, _ := queue.Get()
+				Expect(item).To(Equal(request))
+				queue.Done(item)
+			}
+
+			By("removing the label of one namespace")
+			old := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "lose-label"}, old)).To(Succeed())
+			updated := old.DeepCopy()
+			delete(updated.Labels, "env")
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+			handler.Update(ctx, event.UpdateEvent{ObjectOld: old, ObjectNew: updated}, queue)
+			expectQueued()
+
+			By("finding the policy through the index when only the new labels are known")
+			handler.Generic(ctx, event.GenericEvent{Object: updated}, queue)
+			expectQueued()
+			Expect(matchedNamespaces()).To(Equal(1))
+			handler.Generic(ctx, event.GenericEvent{Object: updated}, queue)
+			Expect(queue.Len()).To(BeZero())
+
+			By("deleting the other namespace")
+			deleted := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "lose-delete"}, deleted)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, deleted)).To(Succeed())
+			handler.Delete(ctx, event.DeleteEvent{Object: deleted}, queue)
+			expectQueued()
+			// envtest runs no namespace controller, so the namespace stays
+			// Terminating; it must no longer be counted either way.
+			Expect(matchedNamespaces()).To(Equal(0))
+		})
+	})
+
+	Context("When pods of a matched namespace change", func() {
+		ctx := context.Background()
+
+		It("should fold their events into one delayed reconcile of the policy", func() {
+			By("creating a matching namespace and a policy")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "pod-events",
+				Labels: map[string]string{"env": "pod-events"},
+			}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			resource := &syncv1beta1.TimeSyncPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-events-policy"},
+				Spec: syncv1beta1.TimeSyncPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "pod-events"}},
+					Enable:            true,
+					Template:          syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			controllerReconciler := &TimeSyncPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: resource.Name}}
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler := controllerReconciler.podHandler()
+			clock := testingclock.NewFakeClock(time.Now())
+			queue := workqueue.NewTypedRateLimitingQueueWithConfig(
+				workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+				workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{Clock: clock},
+			)
+			DeferCleanup(queue.ShutDown)
+
+			By("sending events for many pods of the namespace")
+			for i := range 50 {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: ns.Name}}
+				handler.Create(ctx, event.CreateEvent{Object: pod}, queue)
+				handler.Update(ctx, event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}, queue)
+			}
+			handler.Create(ctx, event.CreateEvent{Object: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "default"},
+			}}, queue)
+			Expect(queue.Len()).To(BeZero())
+
+			By("reconciling the policy once after PodEventDelay")
+			Eventually(func() int {
+				clock.Step(PodEventDelay)
+				return queue.Len()
+			}).Should(Equal(1))
+			item, _ := queue.Get()
+			Expect(item).To(Equal(request))
+			queue.Done(item)
+			Consistently(queue.Len, "200ms").Should(BeZero())
+		})
+	})
+
+	Context("When filtering namespace events", func() {
+		It("should pass label changes and deletion but not other updates", func() {
+			pred := predicate.Or[client.Object](predicate.LabelChangedPredicate{}, deletionStartedPredicate())
+			old := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Labels: map[string]string{"env": "a"}}}
+
+			annotated := old.DeepCopy()
+			annotated.Annotations = map[string]string{"note": "x"}
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: annotated})).To(BeFalse())
+
+			relabeled := old.DeepCopy()
+			relabeled.Labels["env"] = "b"
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: relabeled})).To(BeTrue())
+
+			terminating := old.DeepCopy()
+			terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: terminating})).To(BeTrue())
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: terminating, ObjectNew: terminating})).To(BeFalse())
+		})
+	})
 })
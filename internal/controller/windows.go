@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// applySpec records the spec as applied when suspend and activeWindows allow
+// changes at now, and reports whether they do. It returns how long until the
+// next window opens, or zero when there is none.
+func applySpec(tsp *syncv1beta1.TimeSyncPolicy, now time.Time) (bool, time.Duration) {
+	allowed, next, err := policy.ChangesAllowed(&tsp.Spec, now)
+	if allowed {
+		tsp.Status.AppliedSpec = tsp.Spec.DeepCopy()
+		tsp.Status.AppliedGeneration = tsp.Generation
+	}
+	tsp.Status.NextWindow = nil
+	var wait time.Duration
+	if !next.IsZero() {
+		tsp.Status.NextWindow = &metav1.Time{Time: next}
+		wait = next.Sub(now)
+	}
+
+	cond := metav1.Condition{
+		Type:               syncv1beta1.ConditionSpecApplied,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: tsp.Generation,
+	}
+	switch {
+	case err != nil:
+		cond.Reason = "InvalidWindow"
+		cond.Message = err.Error()
+	case tsp.Status.AppliedGeneration == tsp.Generation:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Applied"
+		cond.Message = "The webhook injects with the current spec"
+	case tsp.Spec.Suspend:
+		cond.Reason = "Suspended"
+		cond.Message = "The policy is suspended"
+	case next.IsZero():
+		cond.Reason = "OutsideWindow"
+		cond.Message = "No active window opens again"
+	default:
+		cond.Reason = "OutsideWindow"
+		cond.Message = fmt.Sprintf("Changes take effect in the window opening at %s", next.UTC().Format(time.RFC3339))
+	}
+	switch {
+	case cond.Status == metav1.ConditionTrue:
+	case tsp.Status.AppliedSpec == nil:
+		cond.Message += "; the policy has not been applied yet and injects nothing"
+	default:
+		cond.Message += fmt.Sprintf("; the webhook injects with generation %d", tsp.Status.AppliedGeneration)
+	}
+	meta.SetStatusCondition(&tsp.Status.Conditions, cond)
+	return allowed, wait
+}
+
+// appliedSpec returns the spec the webhook injects with.
+func appliedSpec(tsp *syncv1beta1.TimeSyncPolicy) *syncv1beta1.TimeSyncPolicySpec {
+	if tsp.Status.AppliedSpec != nil {
+		return tsp.Status.AppliedSpec
+	}
+	return &tsp.Spec
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the operator's Prometheus metrics. They are
+// registered with the controller-runtime registry and served on the
+// manager's metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// SkippedPods counts the pods and pod templates a matching policy did not
+	// inject because of one of its skip rules.
+	SkippedPods = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "timesync_skipped_pods_total",
+		Help: "Pods and pod templates not injected because of a policy skip rule.",
+	}, []string{"reason"})
+
+	// OffsetSeconds is the clock offset last reported by each injected pod.
+	// The pod is named by target_namespace and target_pod, since Prometheus
+	// renames namespace and pod to exported_* for clashing with the labels
+	// of the operator's own scrape target.
+	OffsetSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "timesync_offset_seconds",
+		Help: "Clock offset last reported by an injected pod, in seconds.",
+	}, []string{"policy", "target_namespace", "target_pod"})
+
+	// OutOfSLOPods is the number of injected pods outside their policy's SLO.
+	OutOfSLOPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "timesync_slo_out_of_slo_pods",
+		Help: "Injected pods outside the clock skew SLO of their policy.",
+	}, []string{"policy"})
+
+	// SLOCompliance is the fraction of a policy's injected pods within its
+	// SLO; one minus it is the rate at which the error budget burns.
+	SLOCompliance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "timesync_slo_compliance_ratio",
+		Help: "Fraction of injected pods within the clock skew SLO of their policy.",
+	}, []string{"policy"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(SkippedPods, OffsetSeconds, OutOfSLOPods, SLOCompliance)
+}
+
+// ForgetPolicy drops the series of a policy, before they are set again or
+// once the policy is gone.
+func ForgetPolicy(name string) {
+	OffsetSeconds.DeletePartialMatch(prometheus.Labels{"policy": name})
+	OutOfSLOPods.DeleteLabelValues(name)
+	SLOCompliance.DeleteLabelValues(name)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// SidecarRequirement is the CEL expression, for a ValidatingAdmissionPolicy,
+// that holds for pods carrying the timesync container.
+var SidecarRequirement = fmt.Sprintf("object.spec.containers.exists(c, c.name == %s)", strconv.Quote(SidecarName))
+
+// ExemptionExpression returns a CEL expression, for a
+// ValidatingAdmissionPolicy matching the pods the policy selects by label,
+// that holds for the pods the webhook would still leave without a sidecar:
+// those excluded by name, skipped, exempted by an exception active at now,
+// in a namespace listed in optedOut, or failing a match condition. It leaves
+// out the rollout, which cannot be computed in CEL.
+func ExemptionExpression(p *syncv1beta1.TimeSyncPolicy, exceptions []syncv1beta1.TimeSyncException, optedOut []string, now time.Time) string {
+	var terms []string
+	if namespaces := append(slices.Clone(p.Spec.ExcludeNamespaces), optedOut...); len(namespaces) > 0 {
+		slices.Sort(namespaces)
+		terms = append(terms, fmt.Sprintf("request.namespace in %s", celList(slices.Compact(namespaces))))
+	}
+	for _, pattern := range p.Spec.ExcludePods {
+		// Pods created by controllers are only named after admission.
+		target := "(has(object.metadata.name) ? object.metadata.name : object.metadata.generateName)"
+		if strings.Contains(pattern, "/") {
+			target = "request.namespace + '/' + " + target
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			terms = append(terms, fmt.Sprintf("(%s).startsWith(%s)", target, strconv.Quote(prefix)))
+		} else {
+			terms = append(terms, fmt.Sprintf("%s == %s", target, strconv.Quote(pattern)))
+		}
+	}
+	for _, reason := range p.Spec.Skip {
+		if term := skipExpression(reason, p.Spec.NodeDaemonLabel); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	for i := range exceptions {
+		e := &exceptions[i]
+		if !ExceptionActive(e, now) || (len(e.Spec.Policies) > 0 && !slices.Contains(e.Spec.Policies, p.Name)) {
+			continue
+		}
+		terms = append(terms, exceptionExpression(e))
+	}
+	if len(p.Spec.MatchConditions) > 0 {
+		conditions := make([]string, 0, len(p.Spec.MatchConditions))
+		for _, c := range p.Spec.MatchConditions {
+			conditions = append(conditions, "("+c.Expression+")")
+		}
+		terms = append(terms, "!("+strings.Join(conditions, " && ")+")")
+	}
+	if len(terms) == 0 {
+		return "false"
+	}
+	return strings.Join(terms, " ||\n")
+}
+
+// skipExpression mirrors skips in CEL.
+func skipExpression(reason syncv1beta1.SkipReason, nodeDaemonLabel string) string {
+	switch reason {
+	case syncv1beta1.SkipReasonWindows:
+		return "has(object.spec.os) && object.spec.os.name == 'windows'"
+	case syncv1beta1.SkipReasonHostNetwork:
+		return "has(object.spec.hostNetwork) && object.spec.hostNetwork"
+	case syncv1beta1.SkipReasonMirrorPod:
+		return fmt.Sprintf("has(object.metadata.annotations) && %s in object.metadata.annotations",
+			strconv.Quote(corev1.MirrorPodAnnotationKey))
+	case syncv1beta1.SkipReasonDaemonSet:
+		return "has(object.metadata.ownerReferences) && object.metadata.ownerReferences.exists(r, " +
+			"has(r.controller) && r.controller && r.kind == 'DaemonSet')"
+	case syncv1beta1.SkipReasonNodeDaemon:
+		if nodeDaemonLabel != "" {
+			return fmt.Sprintf("has(object.spec.nodeSelector) && %s in object.spec.nodeSelector", strconv.Quote(nodeDaemonLabel))
+		}
+	}
+	return ""
+}
+
+// exceptionExpression mirrors ExceptionCoversPod in CEL.
+func exceptionExpression(e *syncv1beta1.TimeSyncException) string {
+	namespaces := "true"
+	switch {
+	case len(e.Spec.Namespaces) > 0 && e.Spec.NamespaceSelector != nil:
+		namespaces = fmt.Sprintf("(request.namespace in %s || %s)", celList(e.Spec.Namespaces),
+			selectorExpression(e.Spec.NamespaceSelector, "namespaceObject.metadata.labels"))
+	case len(e.Spec.Namespaces) > 0:
+		namespaces = fmt.Sprintf("request.namespace in %s", celList(e.Spec.Namespaces))
+	case e.Spec.NamespaceSelector != nil:
+		namespaces = selectorExpression(e.Spec.NamespaceSelector, "namespaceObject.metadata.labels")
+	}
+	pods := "true"
+	if e.Spec.PodSelector != nil {
+		pods = selectorExpression(e.Spec.PodSelector, "object.metadata.labels")
+	}
+	return fmt.Sprintf("(%s && %s)", namespaces, pods)
+}
+
+// selectorExpression returns a CEL expression that holds when the labels
+// at the field path labels match the selector.
+func selectorExpression(selector *metav1.LabelSelector, labels string) string {
+	var terms []string
+	for _, key := range slices.Sorted(maps.Keys(selector.MatchLabels)) {
+		terms = append(terms, fmt.Sprintf("has(%[1]s) && %[2]s in %[1]s && %[1]s[%[2]s] == %[3]s",
+			labels, strconv.Quote(key), strconv.Quote(selector.MatchLabels[key])))
+	}
+	for _, req := range selector.MatchExpressions {
+		key := strconv.Quote(req.Key)
+		present := fmt.Sprintf("has(%[1]s) && %[2]s in %[1]s", labels, key)
+		switch req.Operator {
+		case metav1.LabelSelectorOpIn:
+			terms = append(terms, fmt.Sprintf("%s && %s[%s] in %s", present, labels, key, celList(req.Values)))
+		case metav1.LabelSelectorOpNotIn:
+			terms = append(terms, fmt.Sprintf("!(%s && %s[%s] in %s)", present, labels, key, celList(req.Values)))
+		case metav1.LabelSelectorOpExists:
+			terms = append(terms, present)
+		case metav1.LabelSelectorOpDoesNotExist:
+			terms = append(terms, "!("+present+")")
+		default:
+			// An invalid selector matches nothing, as it does for the webhook.
+			return "false"
+		}
+	}
+	if len(terms) == 0 {
+		return "true"
+	}
+	return "(" + strings.Join(terms, ") && (") + ")"
+}
+
+func celList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
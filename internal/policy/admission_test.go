@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// evalAdmission evaluates the generated expressions the way the API server
+// would for pod, reporting whether it is admitted.
+func evalAdmission(t *testing.T, exemption string, ns *corev1.Namespace, pod *corev1.Pod) bool {
+	t.Helper()
+	vars, err := conditionVars(ns, pod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars["request"].(map[string]any)["namespace"] = ns.Name
+	env, err := celEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ast, issues := env.Compile("(" + exemption + ") || " + SidecarRequirement)
+	if issues.Err() != nil {
+		t.Fatalf("compiling %q: %v", exemption, issues.Err())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		t.Fatalf("evaluating %q: %v", exemption, err)
+	}
+	return out.Value().(bool)
+}
+
+func TestExemptionExpression(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+	p := newPolicy("cluster", true, "img", nil)
+	p.Spec.Skip = []syncv1beta1.SkipReason{syncv1beta1.SkipReasonHostNetwork, syncv1beta1.SkipReasonDaemonSet}
+	p.Spec.ExcludeNamespaces = []string{"kube-system"}
+	p.Spec.ExcludePods = []string{"debug-*", "ns/legacy"}
+	p.Spec.MatchConditions = []syncv1beta1.MatchCondition{{Name: "no-ntpd", Expression: "!object.spec.containers.exists(c, c.name == 'ntpd')"}}
+	exceptions := []syncv1beta1.TimeSyncException{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ledger"},
+			Spec: syncv1beta1.TimeSyncExceptionSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "billing"}},
+				PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"ledger", "invoice"}},
+				}},
+				ExpiresAt: metav1.NewTime(now.Add(time.Hour)),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "expired"},
+			Spec:       syncv1beta1.TimeSyncExceptionSpec{ExpiresAt: metav1.NewTime(now)},
+		},
+	}
+	exemption := ExemptionExpression(&p, exceptions, []string{"opted-out"}, now)
+
+	ns := newNamespace("ns", map[string]string{"team": "billing"})
+	pod := func(mutate func(*corev1.Pod)) *corev1.Pod {
+		pod := newPod("ns", "web-0", map[string]string{"app": "web"})
+		pod.Spec.Containers = []corev1.Container{{Name: "app"}}
+		if mutate != nil {
+			mutate(pod)
+		}
+		return pod
+	}
+	tests := []struct {
+		name  string
+		ns    *corev1.Namespace
+		pod   *corev1.Pod
+		admit bool
+	}{
+		{name: "missing sidecar", ns: ns, pod: pod(nil)},
+		{name: "with sidecar", ns: ns, pod: pod(func(p *corev1.Pod) {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: SidecarName})
+		}), admit: true},
+		{name: "excluded namespace", ns: newNamespace("kube-system", nil), pod: pod(nil), admit: true},
+		{name: "opted out namespace", ns: newNamespace("opted-out", nil), pod: pod(nil), admit: true},
+		{name: "excluded pod prefix", ns: ns, pod: pod(func(p *corev1.Pod) { p.Name = "debug-1" }), admit: true},
+		{name: "excluded pod by generateName", ns: ns, pod: pod(func(p *corev1.Pod) {
+			p.Name, p.GenerateName = "", "debug-"
+		}), admit: true},
+		{name: "excluded pod by namespace", ns: ns, pod: pod(func(p *corev1.Pod) { p.Name = "legacy" }), admit: true},
+		{name: "host network", ns: ns, pod: pod(func(p *corev1.Pod) { p.Spec.HostNetwork = true }), admit: true},
+		{name: "daemonset", ns: ns, pod: pod(func(p *corev1.Pod) {
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: ptr.To(true)}}
+		}), admit: true},
+		{name: "exception", ns: ns, pod: pod(func(p *corev1.Pod) { p.Labels["app"] = "ledger" }), admit: true},
+		{name: "exception in other namespace", ns: newNamespace("other", nil), pod: pod(func(p *corev1.Pod) {
+			p.Labels["app"] = "ledger"
+		})},
+		{name: "match condition false", ns: ns, pod: pod(func(p *corev1.Pod) {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: "ntpd"})
+		}), admit: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pod.Namespace = tt.ns.Name
+			if got := evalAdmission(t, exemption, tt.ns, tt.pod); got != tt.admit {
+				t.Errorf("got admit=%v, want %v for\n%s", got, tt.admit, exemption)
+			}
+		})
+	}
+}
+
+func TestExemptionExpressionWithoutExemptions(t *testing.T) {
+	p := newPolicy("cluster", true, "img", nil)
+	if got := ExemptionExpression(&p, nil, nil, time.Now()); got != "false" {
+		t.Errorf("got %q, want false", got)
+	}
+}
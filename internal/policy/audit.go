@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// AuditAnnotation records on a pod, or a workload, the Audit mode policy
+// that would have injected the sidecar.
+const AuditAnnotation = "sync.example.com/would-inject"
+
+// Audits reports whether the sidecar is only recorded rather than injected.
+func (c *Config) Audits() bool {
+	return c.Mode == syncv1beta1.PolicyModeAudit
+}
+
+// AuditMessage describes what the webhook would have done with an object had
+// the selected policy been enforced. d must select a policy.
+func AuditMessage(d Decision, object string) string {
+	if d.Refusal != nil {
+		return fmt.Sprintf("policy %s would refuse %s: %v", d.Config.PolicyName, object, d.Refusal)
+	}
+	msg := fmt.Sprintf("policy %s would inject the timesync sidecar (image %s) into %s",
+		d.Config.PolicyName, d.Config.Template.Image, object)
+	if len(d.Warnings) > 0 {
+		msg += ", with warnings: " + strings.Join(d.Warnings, "; ")
+	}
+	return msg
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestAuditMessage(t *testing.T) {
+	p := newPolicy("audit", true, "img:1", nil)
+	p.Spec.Mode = syncv1beta1.PolicyModeAudit
+	d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil)
+	if !d.Inject() || !d.Config.Audits() {
+		t.Fatalf("got %+v, want an audited injection", d.Config)
+	}
+
+	tests := []struct {
+		name     string
+		warnings []string
+		refusal  error
+		want     string
+	}{
+		{
+			name: "injection",
+			want: "policy audit would inject the timesync sidecar (image img:1) into pod ns/web",
+		},
+		{
+			name:     "injection with warnings",
+			warnings: []string{"a", "b"},
+			want:     "policy audit would inject the timesync sidecar (image img:1) into pod ns/web, with warnings: a; b",
+		},
+		{
+			name:    "refusal",
+			refusal: errors.New("over quota"),
+			want:    "policy audit would refuse pod ns/web: over quota",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := d
+			d.Warnings, d.Refusal = tt.warnings, tt.refusal
+			if got := AuditMessage(d, "pod ns/web"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigAudits(t *testing.T) {
+	for mode, want := range map[syncv1beta1.PolicyMode]bool{
+		"":                            false,
+		syncv1beta1.PolicyModeEnforce: false,
+		syncv1beta1.PolicyModeAudit:   true,
+	} {
+		if got := (&Config{Mode: mode}).Audits(); got != want {
+			t.Errorf("mode %q: got %v, want %v", mode, got, want)
+		}
+	}
+}
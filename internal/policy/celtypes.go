@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	apiservercel "k8s.io/apiserver/pkg/cel"
+)
+
+// unstructuredTypes maps API types that do not serialize as objects to the
+// CEL type of their unstructured form.
+var unstructuredTypes = map[reflect.Type]*apiservercel.DeclType{
+	reflect.TypeFor[metav1.Time]():          apiservercel.StringType,
+	reflect.TypeFor[metav1.MicroTime]():     apiservercel.StringType,
+	reflect.TypeFor[resource.Quantity]():    apiservercel.StringType,
+	reflect.TypeFor[intstr.IntOrString]():   apiservercel.DynType,
+	reflect.TypeFor[runtime.RawExtension](): apiservercel.DynType,
+}
+
+// declTypeOf describes the unstructured form of an API type to the CEL type
+// checker, so that expressions selecting fields the type does not have are
+// rejected when they are compiled rather than failing on every evaluation.
+func declTypeOf(t reflect.Type) *apiservercel.DeclType {
+	return (&declTypeBuilder{seen: map[reflect.Type]*apiservercel.DeclType{}}).build(t)
+}
+
+type declTypeBuilder struct {
+	seen map[reflect.Type]*apiservercel.DeclType
+}
+
+func (b *declTypeBuilder) build(t reflect.Type) *apiservercel.DeclType {
+	if known, ok := unstructuredTypes[t]; ok {
+		return known
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.build(t.Elem())
+	case reflect.String:
+		return apiservercel.StringType
+	case reflect.Bool:
+		return apiservercel.BoolType
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return apiservercel.IntType
+	case reflect.Float32, reflect.Float64:
+		return apiservercel.DoubleType
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices serialize as base64 strings.
+			return apiservercel.StringType
+		}
+		return apiservercel.NewListType(b.build(t.Elem()), -1)
+	case reflect.Map:
+		return apiservercel.NewMapType(apiservercel.StringType, b.build(t.Elem()), -1)
+	case reflect.Struct:
+		if declType, ok := b.seen[t]; ok {
+			return declType
+		}
+		fields := map[string]*apiservercel.DeclField{}
+		b.addFields(fields, t)
+		name := strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+		declType := apiservercel.NewObjectType(name, fields)
+		b.seen[t] = declType
+		return declType
+	default:
+		return apiservercel.DynType
+	}
+}
+
+// addFields adds the serialized fields of struct type t to fields, flattening
+// inlined members such as TypeMeta.
+func (b *declTypeBuilder) addFields(fields map[string]*apiservercel.DeclField, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" && (f.Anonymous || strings.Contains(opts, "inline")) {
+			inlined := f.Type
+			if inlined.Kind() == reflect.Pointer {
+				inlined = inlined.Elem()
+			}
+			b.addFields(fields, inlined)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = apiservercel.NewDeclField(name, b.build(f.Type), false, nil, nil)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// SysTimeCapability is the capability needed to change the clock.
+const SysTimeCapability corev1.Capability = "SYS_TIME"
+
+// AdjustsClock reports whether the configuration lets the sidecar change the
+// clock.
+func (c *Config) AdjustsClock() bool {
+	return AdjustsClock(c.ClockAdjustment)
+}
+
+// AdjustsClock reports whether the adjustment mode needs CAP_SYS_TIME.
+func AdjustsClock(mode syncv1beta1.ClockAdjustment) bool {
+	return mode == syncv1beta1.ClockAdjustmentSlew || mode == syncv1beta1.ClockAdjustmentStep
+}
+
+// ClockAdjustmentPermitted reports whether any of the allowlists lets the
+// namespace adjust the clock. An allowlist with an invalid selector still
+// permits the namespaces it lists by name.
+func ClockAdjustmentPermitted(allowlists []syncv1beta1.ClockAdjustmentAllowlist, ns *corev1.Namespace) bool {
+	for _, a := range allowlists {
+		if slices.Contains(a.Spec.Namespaces, ns.Name) {
+			return true
+		}
+		if a.Spec.NamespaceSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(a.Spec.NamespaceSelector)
+		if err == nil && selector.Matches(labels.Set(ns.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyClockAdjustment grants CAP_SYS_TIME to the sidecar when the selected
+// policy asks to adjust the clock and an allowlist permits the namespace.
+// Otherwise the sidecar falls back to not adjusting the clock, with a warning.
+// CAP_SYS_TIME listed in the template itself is always dropped, so that it is
+// only granted through clockAdjustment and the allowlists.
+func ApplyClockAdjustment(d Decision, ns *corev1.Namespace, allowlists []syncv1beta1.ClockAdjustmentAllowlist) Decision {
+	if d.Config == nil {
+		return d
+	}
+	if !d.Config.AdjustsClock() {
+		if slices.Contains(d.Config.Template.Capabilities, SysTimeCapability) {
+			cfg := *d.Config
+			cfg.Template.Capabilities = withoutSysTime(cfg.Template.Capabilities)
+			d.Config = &cfg
+		}
+		return d
+	}
+
+	cfg := *d.Config
+	d.Config = &cfg
+	if !ClockAdjustmentPermitted(allowlists, ns) {
+		warning := fmt.Sprintf("clockAdjustment %s is not permitted in namespace %q; the clock will not be adjusted",
+			cfg.ClockAdjustment, ns.Name)
+		cfg.ClockAdjustment = syncv1beta1.ClockAdjustmentNone
+		cfg.Template.Capabilities = withoutSysTime(cfg.Template.Capabilities)
+		d.Warnings = append(d.Warnings, warning)
+		d.Trace = append(d.Trace, Step{Policy: cfg.PolicyName, Outcome: OutcomeNotPermitted, Message: warning})
+		return d
+	}
+
+	if !slices.Contains(cfg.Template.Capabilities, SysTimeCapability) {
+		cfg.Template.Capabilities = append(slices.Clone(cfg.Template.Capabilities), SysTimeCapability)
+	}
+	return d
+}
+
+// withoutSysTime returns a copy of caps without CAP_SYS_TIME.
+func withoutSysTime(caps []corev1.Capability) []corev1.Capability {
+	return slices.DeleteFunc(slices.Clone(caps), func(c corev1.Capability) bool {
+		return c == SysTimeCapability
+	})
+}
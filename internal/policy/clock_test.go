@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func newAllowlist(namespaces []string, matchLabels map[string]string) syncv1beta1.ClockAdjustmentAllowlist {
+	a := syncv1beta1.ClockAdjustmentAllowlist{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow"},
+		Spec:       syncv1beta1.ClockAdjustmentAllowlistSpec{Namespaces: namespaces},
+	}
+	if matchLabels != nil {
+		a.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: matchLabels}
+	}
+	return a
+}
+
+func TestClockAdjustmentPermitted(t *testing.T) {
+	ns := newNamespace("ns", map[string]string{"clock": "adjust"})
+	tests := []struct {
+		name       string
+		allowlists []syncv1beta1.ClockAdjustmentAllowlist
+		want       bool
+	}{
+		{name: "no allowlist"},
+		{name: "listed by name", allowlists: []syncv1beta1.ClockAdjustmentAllowlist{newAllowlist([]string{"ns"}, nil)}, want: true},
+		{name: "selected by label", allowlists: []syncv1beta1.ClockAdjustmentAllowlist{newAllowlist(nil, map[string]string{"clock": "adjust"})}, want: true},
+		{name: "other namespace", allowlists: []syncv1beta1.ClockAdjustmentAllowlist{newAllowlist([]string{"other"}, map[string]string{"clock": "no"})}},
+		{name: "empty allowlist", allowlists: []syncv1beta1.ClockAdjustmentAllowlist{newAllowlist(nil, nil)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClockAdjustmentPermitted(tt.allowlists, ns); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyClockAdjustment(t *testing.T) {
+	permitted := []syncv1beta1.ClockAdjustmentAllowlist{newAllowlist([]string{"ns"}, nil)}
+
+	tests := []struct {
+		name           string
+		nsLabels       map[string]string
+		adjustment     syncv1beta1.ClockAdjustment
+		templateCaps   []corev1.Capability
+		allowlists     []syncv1beta1.ClockAdjustmentAllowlist
+		wantAdjustment syncv1beta1.ClockAdjustment
+		wantCaps       []corev1.Capability
+		wantTrace      []Outcome
+	}{
+		{
+			name:           "no adjustment needs no permission",
+			adjustment:     syncv1beta1.ClockAdjustmentNone,
+			wantAdjustment: syncv1beta1.ClockAdjustmentNone,
+			wantTrace:      []Outcome{OutcomeSelected},
+		},
+		{
+			name:           "SYS_TIME in the template of a policy without adjustment is dropped",
+			adjustment:     syncv1beta1.ClockAdjustmentNone,
+			templateCaps:   []corev1.Capability{SysTimeCapability, "NET_BIND_SERVICE"},
+			allowlists:     permitted,
+			wantAdjustment: syncv1beta1.ClockAdjustmentNone,
+			wantCaps:       []corev1.Capability{"NET_BIND_SERVICE"},
+			wantTrace:      []Outcome{OutcomeSelected},
+		},
+		{
+			name:           "permitted slew grants SYS_TIME",
+			adjustment:     syncv1beta1.ClockAdjustmentSlew,
+			allowlists:     permitted,
+			wantAdjustment: syncv1beta1.ClockAdjustmentSlew,
+			wantCaps:       []corev1.Capability{SysTimeCapability},
+			wantTrace:      []Outcome{OutcomeSelected},
+		},
+		{
+			name:           "unpermitted step falls back to none",
+			adjustment:     syncv1beta1.ClockAdjustmentStep,
+			wantAdjustment: syncv1beta1.ClockAdjustmentNone,
+			wantTrace:      []Outcome{OutcomeSelected, OutcomeNotPermitted},
+		},
+		{
+			name:           "unpermitted step drops SYS_TIME from the template",
+			adjustment:     syncv1beta1.ClockAdjustmentStep,
+			templateCaps:   []corev1.Capability{SysTimeCapability},
+			wantAdjustment: syncv1beta1.ClockAdjustmentNone,
+			wantCaps:       []corev1.Capability{},
+			wantTrace:      []Outcome{OutcomeSelected, OutcomeNotPermitted},
+		},
+		{
+			name:           "restricted namespace drops a permitted adjustment",
+			nsLabels:       map[string]string{EnforceLabel: "restricted"},
+			adjustment:     syncv1beta1.ClockAdjustmentStep,
+			allowlists:     permitted,
+			wantAdjustment: syncv1beta1.ClockAdjustmentNone,
+			wantCaps:       []corev1.Capability{},
+			wantTrace:      []Outcome{OutcomeSelected, OutcomeDowngraded},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("p", true, "img", nil)
+			p.Spec.ClockAdjustment = tt.adjustment
+			p.Spec.Template.Capabilities = tt.templateCaps
+			ns := newNamespace("ns", tt.nsLabels)
+
+			d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, ns, nil)
+			d = ApplyPodSecurity(ApplyClockAdjustment(d, ns, tt.allowlists), ns)
+			if !d.Inject() {
+				t.Fatal("expected a sidecar configuration")
+			}
+			if d.Config.ClockAdjustment != tt.wantAdjustment {
+				t.Errorf("clockAdjustment: got %q, want %q", d.Config.ClockAdjustment, tt.wantAdjustment)
+			}
+			if got := d.Config.Template.Capabilities; !reflect.DeepEqual(got, tt.wantCaps) {
+				t.Errorf("capabilities: got %#v, want %#v", got, tt.wantCaps)
+			}
+			var outcomes []Outcome
+			for _, s := range d.Trace {
+				outcomes = append(outcomes, s.Outcome)
+			}
+			if !reflect.DeepEqual(outcomes, tt.wantTrace) {
+				t.Errorf("trace: got %v, want %v", outcomes, tt.wantTrace)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+	admissioncel "k8s.io/apiserver/pkg/admission/plugin/cel"
+	apiservercel "k8s.io/apiserver/pkg/cel"
+	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/utils/lru"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// matchConditionCostLimit bounds the work a single match condition may do
+// while a pod waits for admission.
+const matchConditionCostLimit = 1_000_000
+
+// MatchConditionError reports a match condition that does not compile.
+type MatchConditionError struct {
+	Name string
+	Err  error
+}
+
+func (e *MatchConditionError) Error() string {
+	return fmt.Sprintf("invalid matchCondition %q: %v", e.Name, e.Err)
+}
+
+func (e *MatchConditionError) Unwrap() error {
+	return e.Err
+}
+
+type matchCondition struct {
+	name    string
+	program cel.Program
+}
+
+var (
+	celEnv      = sync.OnceValues(newCELEnv)
+	celPrograms = lru.New(256)
+)
+
+// newCELEnv declares the variables match conditions see with the same types
+// and libraries as the API server gives ValidatingAdmissionPolicy, so a typo
+// in a field name is a compile error rather than a condition that never
+// holds.
+func newCELEnv() (*cel.Env, error) {
+	objectType := declTypeOf(reflect.TypeFor[corev1.Pod]())
+	namespaceType := admissioncel.BuildNamespaceType()
+	requestType := admissioncel.BuildRequestType()
+	envSet, err := environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion(), true).Extend(
+		environment.VersionedOptions{
+			IntroducedVersion: version.MajorMinor(1, 0),
+			EnvOptions: []cel.EnvOption{
+				cel.Variable("object", objectType.CelType()),
+				cel.Variable("namespaceObject", namespaceType.CelType()),
+				cel.Variable("request", requestType.CelType()),
+			},
+			DeclTypes: []*apiservercel.DeclType{objectType, namespaceType, requestType},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return envSet.Env(environment.NewExpressions)
+}
+
+// compileConditions type-checks the match conditions and prepares them for
+// evaluation. Programs are cached by expression, since policies are compiled
+// on every admission.
+func compileConditions(conditions []syncv1beta1.MatchCondition) ([]matchCondition, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]matchCondition, 0, len(conditions))
+	for _, c := range conditions {
+		if cached, ok := celPrograms.Get(c.Expression); ok {
+			out = append(out, matchCondition{name: c.Name, program: cached.(cel.Program)})
+			continue
+		}
+		ast, issues := env.Compile(c.Expression)
+		if issues.Err() != nil {
+			return nil, &MatchConditionError{Name: c.Name, Err: issues.Err()}
+		}
+		if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+			return nil, &MatchConditionError{Name: c.Name, Err: fmt.Errorf("expression evaluates to %s, not bool", t)}
+		}
+		program, err := env.Program(ast, cel.CostLimit(matchConditionCostLimit))
+		if err != nil {
+			return nil, &MatchConditionError{Name: c.Name, Err: err}
+		}
+		celPrograms.Add(c.Expression, program)
+		out = append(out, matchCondition{name: c.Name, program: program})
+	}
+	return out, nil
+}
+
+// conditionVars builds the variables match conditions are evaluated with.
+func conditionVars(ns *corev1.Namespace, pod *corev1.Pod, user *authenticationv1.UserInfo) (map[string]any, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return nil, err
+	}
+	namespaceObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ns)
+	if err != nil {
+		return nil, err
+	}
+	userInfo := map[string]any{}
+	if user != nil {
+		if userInfo, err = runtime.DefaultUnstructuredConverter.ToUnstructured(user); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]any{
+		"object":          object,
+		"namespaceObject": namespaceObject,
+		"request":         map[string]any{"userInfo": userInfo},
+	}, nil
+}
+
+// conditionEvaluator evaluates the match conditions of policies against one
+// pod, building their variables only once.
+type conditionEvaluator struct {
+	ns   *corev1.Namespace
+	pod  *corev1.Pod
+	user *authenticationv1.UserInfo
+
+	vars map[string]any
+	err  error
+	// reason says why the last call to hold returned false.
+	reason string
+}
+
+// hold reports whether every match condition of the matcher holds for the
+// pod.
+func (e *conditionEvaluator) hold(m *Matcher) bool {
+	if len(m.conditions) == 0 {
+		return true
+	}
+	if e.vars == nil && e.err == nil {
+		e.vars, e.err = conditionVars(e.ns, e.pod, e.user)
+	}
+	if e.err != nil {
+		e.reason = fmt.Sprintf("match conditions not evaluated: %v", e.err)
+		return false
+	}
+	matched, name, err := m.matchesConditions(e.vars)
+	switch {
+	case err != nil:
+		e.reason = fmt.Sprintf("match condition %q failed: %v", name, err)
+	case !matched:
+		e.reason = fmt.Sprintf("match condition %q is false", name)
+	}
+	return matched
+}
+
+// matchesConditions reports whether every match condition of the policy
+// holds for vars, naming the first one that does not. A condition that
+// fails to evaluate does not hold.
+func (m *Matcher) matchesConditions(vars map[string]any) (bool, string, error) {
+	for _, c := range m.conditions {
+		out, _, err := c.program.Eval(vars)
+		if err != nil {
+			return false, c.name, err
+		}
+		matched, ok := out.Value().(bool)
+		if !ok {
+			return false, c.name, fmt.Errorf("expression evaluated to %s, not bool", out.Type())
+		}
+		if !matched {
+			return false, c.name, nil
+		}
+	}
+	return true, "", nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func withConditions(p syncv1beta1.TimeSyncPolicy, expressions ...string) syncv1beta1.TimeSyncPolicy {
+	for i, expr := range expressions {
+		p.Spec.MatchConditions = append(p.Spec.MatchConditions, syncv1beta1.MatchCondition{
+			Name:       string(rune('a' + i)),
+			Expression: expr,
+		})
+	}
+	return p
+}
+
+func TestResolveMatchConditions(t *testing.T) {
+	env := map[string]string{"env": "test"}
+	jobPod := newPod("ns", "job-pod", nil)
+	jobPod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "backup"}}
+	ntpPod := newPod("ns", "ntp-pod", nil)
+	ntpPod.Spec.Containers = []corev1.Container{{Name: "app"}, {Name: "ntpd"}}
+	user := &authenticationv1.UserInfo{Username: "system:serviceaccount:ci:deployer", Groups: []string{"ci"}}
+
+	tests := []struct {
+		name       string
+		conditions []string
+		pod        *corev1.Pod
+		wantInject bool
+		wantReason string
+	}{
+		{
+			name:       "owner is a Job",
+			conditions: []string{"has(object.metadata.ownerReferences) && object.metadata.ownerReferences.exists(r, r.kind == 'Job')"},
+			pod:        jobPod,
+			wantInject: true,
+		},
+		{
+			name:       "owner is not a Job",
+			conditions: []string{"has(object.metadata.ownerReferences) && object.metadata.ownerReferences.exists(r, r.kind == 'Job')"},
+			pod:        ntpPod,
+			wantReason: `match condition "a" is false`,
+		},
+		{
+			name:       "pod runs its own NTP container",
+			conditions: []string{"!object.spec.containers.exists(c, c.name == 'ntpd')"},
+			pod:        ntpPod,
+			wantReason: `match condition "a" is false`,
+		},
+		{
+			name:       "namespace and user",
+			conditions: []string{"namespaceObject.metadata.labels.env == 'test'", "'ci' in request.userInfo.groups"},
+			pod:        jobPod,
+			wantInject: true,
+		},
+		{
+			name:       "second condition false",
+			conditions: []string{"true", "request.userInfo.username.startsWith('system:admin')"},
+			pod:        jobPod,
+			wantReason: `match condition "b" is false`,
+		},
+		{
+			name:       "evaluation error",
+			conditions: []string{"object.metadata.annotations['missing'] == 'x'"},
+			pod:        jobPod,
+			wantReason: `match condition "a" failed`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := withConditions(newPolicy("cluster", true, "img", env), tt.conditions...)
+			d := ResolveFor([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", env), tt.pod, user)
+			if d.Inject() != tt.wantInject {
+				t.Fatalf("got inject=%v, want %v; trace %v", d.Inject(), tt.wantInject, d.Trace)
+			}
+			if !tt.wantInject && !strings.HasPrefix(d.Trace[0].Message, tt.wantReason) {
+				t.Errorf("got trace %v, want %q", d.Trace, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCompileRejectsInvalidMatchConditions(t *testing.T) {
+	for _, expr := range []string{
+		"object.metadata.name ==",
+		"unknown.field",
+		"'not a bool'",
+		"object.metdata.labels.env == 'test'",
+		"object.spec.containers.exists(c, c.imagee == 'ntpd')",
+		"namespaceObject.metadata.label.env == 'test'",
+		"request.userInfo.group.exists(g, g == 'ci')",
+	} {
+		p := withConditions(newPolicy("cluster", true, "img", nil), expr)
+		_, err := Compile(&p)
+		var conditionErr *MatchConditionError
+		if !errors.As(err, &conditionErr) || conditionErr.Name != "a" {
+			t.Errorf("%q: got error %v, want a MatchConditionError", expr, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// ExemptedAnnotation records on a pod, or a workload, the TimeSyncException
+// that kept its matching policy from injecting the sidecar.
+const ExemptedAnnotation = "sync.example.com/exempted-by"
+
+// ExceptionActive reports whether the exception has not expired at now.
+func ExceptionActive(e *syncv1beta1.TimeSyncException, now time.Time) bool {
+	return now.Before(e.Spec.ExpiresAt.Time)
+}
+
+// ExceptionCoversNamespace reports whether the exception targets pods of the
+// namespace for the policy, whatever their labels.
+func ExceptionCoversNamespace(e *syncv1beta1.TimeSyncException, policyName string, ns *corev1.Namespace) (bool, error) {
+	if len(e.Spec.Policies) > 0 && !slices.Contains(e.Spec.Policies, policyName) {
+		return false, nil
+	}
+	if len(e.Spec.Namespaces) == 0 && e.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	if slices.Contains(e.Spec.Namespaces, ns.Name) {
+		return true, nil
+	}
+	if e.Spec.NamespaceSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(e.Spec.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// ExceptionCoversPod reports whether the exception exempts the pod in the
+// namespace from the policy.
+func ExceptionCoversPod(e *syncv1beta1.TimeSyncException, policyName string, ns *corev1.Namespace, pod *corev1.Pod) (bool, error) {
+	covered, err := ExceptionCoversNamespace(e, policyName, ns)
+	if err != nil || !covered || e.Spec.PodSelector == nil {
+		return covered, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(e.Spec.PodSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid podSelector: %w", err)
+	}
+	return selector.Matches(labels.Set(pod.Labels)), nil
+}
+
+// ApplyExceptions withdraws the sidecar from a pod that an unexpired
+// exception exempts from the selected policy, recording the exception in the
+// decision. Exceptions with invalid selectors are ignored.
+func ApplyExceptions(d Decision, exceptions []syncv1beta1.TimeSyncException, ns *corev1.Namespace, pod *corev1.Pod, now time.Time) Decision {
+	if d.Config == nil || pod == nil {
+		return d
+	}
+	for i := range exceptions {
+		e := &exceptions[i]
+		if !ExceptionActive(e, now) {
+			continue
+		}
+		if covered, err := ExceptionCoversPod(e, d.Config.PolicyName, ns, pod); err != nil || !covered {
+			continue
+		}
+		d.Trace = append(d.Trace, Step{Policy: d.Config.PolicyName, Outcome: OutcomeExempted,
+			Message: fmt.Sprintf("exempted by TimeSyncException %s of %s until %s: %s",
+				e.Name, e.Spec.Owner, e.Spec.ExpiresAt.UTC().Format(time.RFC3339), e.Spec.Reason)})
+		d.Config = nil
+		d.Exception = e.Name
+		return d
+	}
+	return d
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestApplyExceptions(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+	later := metav1.NewTime(now.Add(time.Hour))
+	env := map[string]string{"env": "prod"}
+	exception := func(spec syncv1beta1.TimeSyncExceptionSpec) syncv1beta1.TimeSyncException {
+		if spec.ExpiresAt.IsZero() {
+			spec.ExpiresAt = later
+		}
+		spec.Owner, spec.Reason = "team-a", "legacy NTP client"
+		return syncv1beta1.TimeSyncException{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}, Spec: spec}
+	}
+
+	tests := []struct {
+		name       string
+		exception  syncv1beta1.TimeSyncException
+		wantExempt bool
+	}{
+		{
+			name:       "namespace by name",
+			exception:  exception(syncv1beta1.TimeSyncExceptionSpec{Namespaces: []string{"ns"}}),
+			wantExempt: true,
+		},
+		{
+			name:      "other namespace",
+			exception: exception(syncv1beta1.TimeSyncExceptionSpec{Namespaces: []string{"other"}}),
+		},
+		{
+			name: "namespace by label",
+			exception: exception(syncv1beta1.TimeSyncExceptionSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: env},
+			}),
+			wantExempt: true,
+		},
+		{
+			name: "pod selector in every namespace",
+			exception: exception(syncv1beta1.TimeSyncExceptionSpec{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ledger"}},
+			}),
+			wantExempt: true,
+		},
+		{
+			name: "pod selector not matching",
+			exception: exception(syncv1beta1.TimeSyncExceptionSpec{
+				Namespaces:  []string{"ns"},
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			}),
+		},
+		{
+			name: "other policy",
+			exception: exception(syncv1beta1.TimeSyncExceptionSpec{
+				Namespaces: []string{"ns"},
+				Policies:   []string{"other"},
+			}),
+		},
+		{
+			name: "expired",
+			exception: exception(syncv1beta1.TimeSyncExceptionSpec{
+				Namespaces: []string{"ns"},
+				ExpiresAt:  metav1.NewTime(now),
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := newNamespace("ns", env)
+			d := Resolve([]syncv1beta1.TimeSyncPolicy{newPolicy("cluster", true, "img", env)}, ns, nil)
+			pod := newPod("ns", "ledger-0", map[string]string{"app": "ledger"})
+			d = ApplyExceptions(d, []syncv1beta1.TimeSyncException{tt.exception}, ns, pod, now)
+			if d.Inject() == tt.wantExempt {
+				t.Fatalf("got inject=%v, want exempt=%v; trace %v", d.Inject(), tt.wantExempt, d.Trace)
+			}
+			if tt.wantExempt && (d.Exception != "legacy" || d.Trace[len(d.Trace)-1].Outcome != OutcomeExempted) {
+				t.Errorf("got exception %q and trace %v", d.Exception, d.Trace)
+			}
+		})
+	}
+}
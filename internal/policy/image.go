@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// PinsDigest reports whether the image policy asks for digest pinning.
+func PinsDigest(ip *syncv1beta1.ImagePolicy) bool {
+	return ip != nil && (ip.PinDigest || ip.PublicKey != "")
+}
+
+// VerifiesSignature reports whether the image policy asks for signature
+// verification.
+func VerifiesSignature(ip *syncv1beta1.ImagePolicy) bool {
+	return ip != nil && ip.PublicKey != ""
+}
+
+// PinImage returns image pinned to digest. An image that already carries a
+// digest is returned unchanged.
+func PinImage(image, digest string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+	return image + "@" + digest
+}
+
+// PinnedFrom reports whether resolved is image pinned to a digest.
+func PinnedFrom(resolved, image string) bool {
+	if strings.Contains(image, "@") {
+		return resolved == image
+	}
+	digest, ok := strings.CutPrefix(resolved, image+"@")
+	return ok && digest != ""
+}
+
+// ApplyImagePolicy replaces the sidecar image with the digest the controller
+// resolved for it. Until one is available the tag is injected with a warning,
+// unless the policy requires a verified signature, in which case nothing is
+// injected. A tenant image override is never pinned, since the controller
+// only resolves the cluster policy's image.
+func ApplyImagePolicy(d Decision) Decision {
+	if d.Config == nil || !PinsDigest(d.Config.ImagePolicy) {
+		return d
+	}
+
+	cfg := *d.Config
+	if PinnedFrom(cfg.ResolvedImage, cfg.Template.Image) {
+		cfg.Template.Image = cfg.ResolvedImage
+		d.Config = &cfg
+		return d
+	}
+
+	if VerifiesSignature(cfg.ImagePolicy) {
+		d.Config = nil
+		d.Trace = append(d.Trace, Step{Policy: cfg.PolicyName, Outcome: OutcomeUnverified,
+			Message: fmt.Sprintf("image %q has no verified digest", cfg.Template.Image)})
+		return d
+	}
+	warning := fmt.Sprintf("image %q has not been resolved to a digest; injecting the tag", cfg.Template.Image)
+	d.Warnings = append(d.Warnings, warning)
+	d.Trace = append(d.Trace, Step{Policy: cfg.PolicyName, Outcome: OutcomeUnpinned, Message: warning})
+	return d
+}
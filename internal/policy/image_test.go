@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestApplyImagePolicy(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	pinned := "img:1@" + digest
+
+	tests := []struct {
+		name        string
+		imagePolicy *syncv1beta1.ImagePolicy
+		resolved    string
+		wantInject  bool
+		wantImage   string
+		wantWarning bool
+		wantTrace   []Outcome
+	}{
+		{
+			name:       "no image policy",
+			resolved:   pinned,
+			wantInject: true,
+			wantImage:  "img:1",
+			wantTrace:  []Outcome{OutcomeSelected},
+		},
+		{
+			name:        "pinned digest",
+			imagePolicy: &syncv1beta1.ImagePolicy{PinDigest: true},
+			resolved:    pinned,
+			wantInject:  true,
+			wantImage:   pinned,
+			wantTrace:   []Outcome{OutcomeSelected},
+		},
+		{
+			name:        "not yet resolved falls back to the tag",
+			imagePolicy: &syncv1beta1.ImagePolicy{PinDigest: true},
+			wantInject:  true,
+			wantImage:   "img:1",
+			wantWarning: true,
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeUnpinned},
+		},
+		{
+			name:        "digest resolved for another image",
+			imagePolicy: &syncv1beta1.ImagePolicy{PinDigest: true},
+			resolved:    "img:0@" + digest,
+			wantInject:  true,
+			wantImage:   "img:1",
+			wantWarning: true,
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeUnpinned},
+		},
+		{
+			name:        "verified digest",
+			imagePolicy: &syncv1beta1.ImagePolicy{PublicKey: "key"},
+			resolved:    pinned,
+			wantInject:  true,
+			wantImage:   pinned,
+			wantTrace:   []Outcome{OutcomeSelected},
+		},
+		{
+			name:        "unverified image is withheld",
+			imagePolicy: &syncv1beta1.ImagePolicy{PublicKey: "key"},
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeUnverified},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("p", true, "img:1", nil)
+			p.Spec.ImagePolicy = tt.imagePolicy
+			p.Status.ResolvedImage = tt.resolved
+
+			d := ApplyImagePolicy(Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil))
+			if d.Inject() != tt.wantInject {
+				t.Fatalf("inject: got %v, want %v", d.Inject(), tt.wantInject)
+			}
+			if tt.wantInject && d.Config.Template.Image != tt.wantImage {
+				t.Errorf("image: got %q, want %q", d.Config.Template.Image, tt.wantImage)
+			}
+			if got := len(d.Warnings) > 0; got != tt.wantWarning {
+				t.Errorf("warnings: got %v, want %v", d.Warnings, tt.wantWarning)
+			}
+			var outcomes []Outcome
+			for _, s := range d.Trace {
+				outcomes = append(outcomes, s.Outcome)
+			}
+			if !reflect.DeepEqual(outcomes, tt.wantTrace) {
+				t.Errorf("trace: got %v, want %v", outcomes, tt.wantTrace)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// ApplyOverrides layers the namespace's NamespaceTimeSyncPolicies on top of a
+// decision. Overrides are applied in name order; a field is only changed if
+// the selected cluster policy allows it, otherwise a Locked step is recorded.
+func ApplyOverrides(d Decision, overrides []syncv1alpha1.NamespaceTimeSyncPolicy) Decision {
+	if d.Config == nil || len(overrides) == 0 {
+		return d
+	}
+
+	items := make([]syncv1alpha1.NamespaceTimeSyncPolicy, len(overrides))
+	copy(items, overrides)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	cfg := *d.Config
+	d.Config = &cfg
+	for _, o := range items {
+		spec := o.Spec
+		if spec.Image != "" {
+			d.override(o.Name, syncv1beta1.OverridableFieldImage,
+				fmt.Sprintf("image set to %q", spec.Image),
+				func() { cfg.Template.Image = spec.Image })
+		}
+		if spec.InjectionTarget != "" {
+			d.override(o.Name, syncv1beta1.OverridableFieldInjectionTarget,
+				fmt.Sprintf("injectionTarget set to %q", spec.InjectionTarget),
+				func() { cfg.Target = syncv1beta1.InjectionTarget(spec.InjectionTarget) })
+		}
+		if spec.Enable != nil && !*spec.Enable {
+			if d.override(o.Name, syncv1beta1.OverridableFieldEnable, "namespace opted out", nil) {
+				d.Config = nil
+				d.Trace[len(d.Trace)-1].Outcome = OutcomeOptedOut
+				return d
+			}
+		}
+	}
+	return d
+}
+
+// override records the outcome of a single tenant override and calls apply
+// when the cluster policy allows the field. It reports whether it did.
+func (d *Decision) override(name string, field syncv1beta1.OverridableField, message string, apply func()) bool {
+	if !slices.Contains(d.Config.AllowedOverrides, field) {
+		d.Trace = append(d.Trace, Step{Policy: name, Outcome: OutcomeLocked,
+			Message: fmt.Sprintf("%s is locked by policy %q", field, d.Config.PolicyName)})
+		return false
+	}
+	if apply != nil {
+		apply()
+	}
+	d.Trace = append(d.Trace, Step{Policy: name, Outcome: OutcomeOverridden, Message: message})
+	return true
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func newOverride(name string, spec syncv1alpha1.NamespaceTimeSyncPolicySpec) syncv1alpha1.NamespaceTimeSyncPolicy {
+	return syncv1alpha1.NamespaceTimeSyncPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec:       spec,
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	env := map[string]string{"env": "test"}
+	all := []syncv1beta1.OverridableField{
+		syncv1beta1.OverridableFieldEnable,
+		syncv1beta1.OverridableFieldImage,
+		syncv1beta1.OverridableFieldInjectionTarget,
+	}
+
+	tests := []struct {
+		name       string
+		allowed    []syncv1beta1.OverridableField
+		overrides  []syncv1alpha1.NamespaceTimeSyncPolicy
+		wantInject bool
+		wantImage  string
+		wantTarget syncv1beta1.InjectionTarget
+		wantTrace  []Outcome
+	}{
+		{
+			name:       "no overrides",
+			allowed:    all,
+			wantInject: true,
+			wantImage:  "img:cluster",
+			wantTrace:  []Outcome{OutcomeSelected},
+		},
+		{
+			name:       "allowed image override",
+			allowed:    []syncv1beta1.OverridableField{syncv1beta1.OverridableFieldImage},
+			overrides:  []syncv1alpha1.NamespaceTimeSyncPolicy{newOverride("t", syncv1alpha1.NamespaceTimeSyncPolicySpec{Image: "img:tenant"})},
+			wantInject: true,
+			wantImage:  "img:tenant",
+			wantTrace:  []Outcome{OutcomeSelected, OutcomeOverridden},
+		},
+		{
+			name:       "locked image override",
+			overrides:  []syncv1alpha1.NamespaceTimeSyncPolicy{newOverride("t", syncv1alpha1.NamespaceTimeSyncPolicySpec{Image: "img:tenant"})},
+			wantInject: true,
+			wantImage:  "img:cluster",
+			wantTrace:  []Outcome{OutcomeSelected, OutcomeLocked},
+		},
+		{
+			name:    "mixed allowed and locked fields",
+			allowed: []syncv1beta1.OverridableField{syncv1beta1.OverridableFieldInjectionTarget},
+			overrides: []syncv1alpha1.NamespaceTimeSyncPolicy{newOverride("t", syncv1alpha1.NamespaceTimeSyncPolicySpec{
+				Image:           "img:tenant",
+				InjectionTarget: syncv1alpha1.InjectionTargetWorkloads,
+			})},
+			wantInject: true,
+			wantImage:  "img:cluster",
+			wantTarget: syncv1beta1.InjectionTargetWorkloads,
+			wantTrace:  []Outcome{OutcomeSelected, OutcomeLocked, OutcomeOverridden},
+		},
+		{
+			name:      "tenant opts out",
+			allowed:   all,
+			overrides: []syncv1alpha1.NamespaceTimeSyncPolicy{newOverride("t", syncv1alpha1.NamespaceTimeSyncPolicySpec{Enable: ptr.To(false)})},
+			wantTrace: []Outcome{OutcomeSelected, OutcomeOptedOut},
+		},
+		{
+			name:       "opt out is locked",
+			overrides:  []syncv1alpha1.NamespaceTimeSyncPolicy{newOverride("t", syncv1alpha1.NamespaceTimeSyncPolicySpec{Enable: ptr.To(false)})},
+			wantInject: true,
+			wantImage:  "img:cluster",
+			wantTrace:  []Outcome{OutcomeSelected, OutcomeLocked},
+		},
+		{
+			name:    "later overrides by name win",
+			allowed: all,
+			overrides: []syncv1alpha1.NamespaceTimeSyncPolicy{
+				newOverride("b", syncv1alpha1.NamespaceTimeSyncPolicySpec{Image: "img:b"}),
+				newOverride("a", syncv1alpha1.NamespaceTimeSyncPolicySpec{Image: "img:a"}),
+			},
+			wantInject: true,
+			wantImage:  "img:b",
+			wantTrace:  []Outcome{OutcomeSelected, OutcomeOverridden, OutcomeOverridden},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("cluster", true, "img:cluster", env)
+			p.Spec.AllowedOverrides = tt.allowed
+			d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", env), nil)
+			d = ApplyOverrides(d, tt.overrides)
+
+			if d.Inject() != tt.wantInject {
+				t.Fatalf("got inject=%v, want %v; trace %v", d.Inject(), tt.wantInject, d.Trace)
+			}
+			if tt.wantInject && (d.Config.Template.Image != tt.wantImage || d.Config.Target != tt.wantTarget) {
+				t.Fatalf("got %+v, want image %q target %q", *d.Config, tt.wantImage, tt.wantTarget)
+			}
+			if len(d.Trace) != len(tt.wantTrace) {
+				t.Fatalf("got trace %v, want outcomes %v", d.Trace, tt.wantTrace)
+			}
+			for i, step := range d.Trace {
+				if step.Outcome != tt.wantTrace[i] {
+					t.Errorf("step %d: got %s, want %s", i, step.Outcome, tt.wantTrace[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyOverridesDoesNotMutateInput(t *testing.T) {
+	p := newPolicy("cluster", true, "img:cluster", nil)
+	p.Spec.AllowedOverrides = []syncv1beta1.OverridableField{syncv1beta1.OverridableFieldImage}
+	d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil)
+
+	_ = ApplyOverrides(d, []syncv1alpha1.NamespaceTimeSyncPolicy{
+		newOverride("t", syncv1alpha1.NamespaceTimeSyncPolicySpec{Image: "img:tenant"}),
+	})
+	if d.Config.Template.Image != "img:cluster" {
+		t.Fatalf("input decision was modified: %+v", *d.Config)
+	}
+}
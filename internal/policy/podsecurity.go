@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// EnforceLabel is the namespace label that sets the enforced Pod Security
+// Admission level.
+const EnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// WarningsAnnotation lists the warnings raised while injecting the sidecar.
+const WarningsAnnotation = "sync.example.com/warnings"
+
+// Level is a Pod Security Standards level.
+type Level string
+
+// Levels in order of increasing restriction.
+const (
+	LevelPrivileged Level = "privileged"
+	LevelBaseline   Level = "baseline"
+	LevelRestricted Level = "restricted"
+)
+
+var (
+	// restrictedCapabilities may be added under the restricted level.
+	restrictedCapabilities = sets.New[corev1.Capability]("NET_BIND_SERVICE")
+	// baselineCapabilities may be added under the baseline level.
+	baselineCapabilities = sets.New[corev1.Capability](
+		"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+		"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+	)
+)
+
+// EnforcedLevel returns the Pod Security level enforced on the namespace.
+// Namespaces without the label are privileged, and an unknown value is
+// treated as restricted, as Pod Security Admission does.
+func EnforcedLevel(ns *corev1.Namespace) Level {
+	value, ok := ns.Labels[EnforceLabel]
+	if !ok {
+		return LevelPrivileged
+	}
+	switch level := Level(value); level {
+	case LevelPrivileged, LevelBaseline, LevelRestricted:
+		return level
+	default:
+		return LevelRestricted
+	}
+}
+
+// ForbiddenCapabilities returns the capabilities the level does not allow a
+// container to add.
+func ForbiddenCapabilities(level Level, caps []corev1.Capability) []corev1.Capability {
+	var allowed sets.Set[corev1.Capability]
+	switch level {
+	case LevelPrivileged:
+		return nil
+	case LevelBaseline:
+		allowed = baselineCapabilities
+	default:
+		allowed = restrictedCapabilities
+	}
+
+	var forbidden []corev1.Capability
+	for _, c := range caps {
+		if !allowed.Has(c) {
+			forbidden = append(forbidden, c)
+		}
+	}
+	return forbidden
+}
+
+// ApplyPodSecurity checks the selected sidecar configuration against the Pod
+// Security level of the namespace. Settings the level forbids are dropped
+// with a warning, or the decision is refused if the policy asks for that.
+func ApplyPodSecurity(d Decision, ns *corev1.Namespace) Decision {
+	if d.Config == nil {
+		return d
+	}
+	level := EnforcedLevel(ns)
+	forbidden := ForbiddenCapabilities(level, d.Config.Template.Capabilities)
+	if len(forbidden) == 0 {
+		return d
+	}
+
+	if d.Config.PodSecurityAction == syncv1beta1.PodSecurityActionRefuse {
+		d.Refusal = fmt.Errorf("namespace %q enforces Pod Security level %q, which forbids capabilities %v required by TimeSyncPolicy %q",
+			ns.Name, level, forbidden, d.Config.PolicyName)
+		d.Trace = append(d.Trace, Step{Policy: d.Config.PolicyName, Outcome: OutcomeRefused, Message: d.Refusal.Error()})
+		return d
+	}
+
+	cfg := *d.Config
+	cfg.Template.Capabilities = slices.DeleteFunc(slices.Clone(cfg.Template.Capabilities),
+		func(c corev1.Capability) bool { return slices.Contains(forbidden, c) })
+	if slices.Contains(forbidden, SysTimeCapability) {
+		cfg.ClockAdjustment = syncv1beta1.ClockAdjustmentNone
+	}
+	d.Config = &cfg
+
+	warning := fmt.Sprintf("dropped capabilities %v forbidden by Pod Security level %q of namespace %q",
+		forbidden, level, ns.Name)
+	d.Warnings = append(d.Warnings, warning)
+	d.Trace = append(d.Trace, Step{Policy: d.Config.PolicyName, Outcome: OutcomeDowngraded, Message: warning})
+	return d
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestEnforcedLevel(t *testing.T) {
+	tests := []struct {
+		labels map[string]string
+		want   Level
+	}{
+		{labels: nil, want: LevelPrivileged},
+		{labels: map[string]string{EnforceLabel: "baseline"}, want: LevelBaseline},
+		{labels: map[string]string{EnforceLabel: "restricted"}, want: LevelRestricted},
+		{labels: map[string]string{EnforceLabel: "bogus"}, want: LevelRestricted},
+	}
+	for _, tt := range tests {
+		if got := EnforcedLevel(newNamespace("ns", tt.labels)); got != tt.want {
+			t.Errorf("EnforcedLevel(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPodSecurity(t *testing.T) {
+	restricted := map[string]string{"env": "test", EnforceLabel: "restricted"}
+	baseline := map[string]string{"env": "test", EnforceLabel: "baseline"}
+
+	tests := []struct {
+		name        string
+		nsLabels    map[string]string
+		caps        []corev1.Capability
+		action      syncv1beta1.PodSecurityAction
+		wantCaps    []corev1.Capability
+		wantRefused bool
+		wantWarning bool
+		wantTrace   []Outcome
+	}{
+		{
+			name:      "no capabilities in a restricted namespace",
+			nsLabels:  restricted,
+			wantTrace: []Outcome{OutcomeSelected},
+		},
+		{
+			name:      "allowed capability in a restricted namespace",
+			nsLabels:  restricted,
+			caps:      []corev1.Capability{"NET_BIND_SERVICE"},
+			wantCaps:  []corev1.Capability{"NET_BIND_SERVICE"},
+			wantTrace: []Outcome{OutcomeSelected},
+		},
+		{
+			name:      "privileged namespace allows SYS_TIME",
+			nsLabels:  map[string]string{"env": "test"},
+			caps:      []corev1.Capability{"SYS_TIME"},
+			wantCaps:  []corev1.Capability{"SYS_TIME"},
+			wantTrace: []Outcome{OutcomeSelected},
+		},
+		{
+			name:        "restricted namespace downgrades SYS_TIME",
+			nsLabels:    restricted,
+			caps:        []corev1.Capability{"SYS_TIME", "NET_BIND_SERVICE"},
+			wantCaps:    []corev1.Capability{"NET_BIND_SERVICE"},
+			wantWarning: true,
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeDowngraded},
+		},
+		{
+			name:        "baseline namespace keeps baseline capabilities",
+			nsLabels:    baseline,
+			caps:        []corev1.Capability{"CHOWN", "SYS_TIME"},
+			wantCaps:    []corev1.Capability{"CHOWN"},
+			wantWarning: true,
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeDowngraded},
+		},
+		{
+			name:        "refuse instead of downgrading",
+			nsLabels:    restricted,
+			caps:        []corev1.Capability{"SYS_TIME"},
+			action:      syncv1beta1.PodSecurityActionRefuse,
+			wantCaps:    []corev1.Capability{"SYS_TIME"},
+			wantRefused: true,
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeRefused},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("p", true, "img", map[string]string{"env": "test"})
+			p.Spec.Template.Capabilities = tt.caps
+			p.Spec.PodSecurityAction = tt.action
+			ns := newNamespace("ns", tt.nsLabels)
+
+			d := ApplyPodSecurity(Resolve([]syncv1beta1.TimeSyncPolicy{p}, ns, nil), ns)
+			if !d.Inject() {
+				t.Fatal("expected a sidecar configuration")
+			}
+			if got := d.Config.Template.Capabilities; !reflect.DeepEqual(got, tt.wantCaps) {
+				t.Errorf("capabilities: got %v, want %v", got, tt.wantCaps)
+			}
+			if got := d.Refusal != nil; got != tt.wantRefused {
+				t.Errorf("refused: got %v, want %v", got, tt.wantRefused)
+			}
+			if got := len(d.Warnings) > 0; got != tt.wantWarning {
+				t.Errorf("warnings: got %v, want %v", d.Warnings, tt.wantWarning)
+			}
+			var outcomes []Outcome
+			for _, s := range d.Trace {
+				outcomes = append(outcomes, s.Outcome)
+			}
+			if !reflect.DeepEqual(outcomes, tt.wantTrace) {
+				t.Errorf("trace: got %v, want %v", outcomes, tt.wantTrace)
+			}
+			if !reflect.DeepEqual(p.Spec.Template.Capabilities, tt.caps) {
+				t.Errorf("policy capabilities were mutated: %v", p.Spec.Template.Capabilities)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy resolves which TimeSyncPolicy applies to a pod. It is shared
+// by the controller and the webhook so that status and admission decisions are
+// always computed the same way.
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// SidecarName is the name of the injected timesync container.
+const SidecarName = "timesync"
+
+// Outcome describes how a single step of a resolution was decided.
+type Outcome string
+
+const (
+	// OutcomeSelected marks the policy whose configuration was applied.
+	OutcomeSelected Outcome = "Selected"
+	// OutcomeShadowed marks a matching policy that lost to an earlier one.
+	OutcomeShadowed Outcome = "Shadowed"
+	// OutcomeNoMatch marks a policy whose selector does not match.
+	OutcomeNoMatch Outcome = "NoMatch"
+	// OutcomeDisabled marks a matching policy with enable set to false.
+	OutcomeDisabled Outcome = "Disabled"
+	// OutcomeInvalid marks a policy whose selector cannot be compiled.
+	OutcomeInvalid Outcome = "Invalid"
+	// OutcomeSidecarPresent marks a pod that already carries the sidecar.
+	OutcomeSidecarPresent Outcome = "SidecarPresent"
+	// OutcomeOverridden marks a field replaced by a NamespaceTimeSyncPolicy.
+	OutcomeOverridden Outcome = "Overridden"
+	// OutcomeLocked marks a tenant override rejected by the cluster policy.
+	OutcomeLocked Outcome = "Locked"
+	// OutcomeOptedOut marks a namespace whose tenant disabled injection.
+	OutcomeOptedOut Outcome = "OptedOut"
+	// OutcomeDowngraded marks sidecar settings dropped for Pod Security.
+	OutcomeDowngraded Outcome = "Downgraded"
+	// OutcomeRefused marks an object rejected for Pod Security or quota.
+	OutcomeRefused Outcome = "Refused"
+	// OutcomeNotPermitted marks a clock adjustment no allowlist permits.
+	OutcomeNotPermitted Outcome = "NotPermitted"
+	// OutcomeUnpinned marks an image injected by tag instead of digest.
+	OutcomeUnpinned Outcome = "Unpinned"
+	// OutcomeUnverified marks an image withheld for lack of a signature.
+	OutcomeUnverified Outcome = "Unverified"
+	// OutcomeOverQuota marks a sidecar that breaks a LimitRange or quota.
+	OutcomeOverQuota Outcome = "OverQuota"
+	// OutcomeSkipped marks a pod the selected policy never injects.
+	OutcomeSkipped Outcome = "Skipped"
+	// OutcomeNotRolledOut marks a pod outside the policy's rollout.
+	OutcomeNotRolledOut Outcome = "NotRolledOut"
+	// OutcomeExempted marks a pod exempted by a TimeSyncException.
+	OutcomeExempted Outcome = "Exempted"
+)
+
+// Step is a single entry of a decision trace.
+type Step struct {
+	// Policy is the name of the evaluated policy, empty for pod-level steps.
+	Policy  string
+	Outcome Outcome
+	Message string
+}
+
+// String renders the step for logs and events.
+func (s Step) String() string {
+	if s.Policy == "" {
+		return fmt.Sprintf("%s: %s", s.Outcome, s.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", s.Policy, s.Outcome, s.Message)
+}
+
+// Config is the effective sidecar configuration resolved for a pod.
+type Config struct {
+	// PolicyName is the name of the policy the configuration comes from.
+	PolicyName string
+	// Revision is the hash of the policy revision the configuration comes
+	// from.
+	Revision string
+	// Mode decides whether the sidecar is injected or only audited.
+	Mode     syncv1beta1.PolicyMode
+	Template syncv1beta1.SidecarTemplate
+	Backend  syncv1beta1.Backend
+	Target   syncv1beta1.InjectionTarget
+
+	// Enforcement decides whether pods must keep the injected sidecar.
+	Enforcement syncv1beta1.Enforcement
+
+	// AllowedOverrides are the fields tenants may change for this policy.
+	AllowedOverrides []syncv1beta1.OverridableField
+
+	// ClockAdjustment is how the sidecar may change the clock.
+	ClockAdjustment syncv1beta1.ClockAdjustment
+
+	// ImagePullSecrets are the names of the Secrets the sidecar is pulled with.
+	ImagePullSecrets []string
+
+	// ImagePolicy and ResolvedImage pin the template image to a digest.
+	ImagePolicy   *syncv1beta1.ImagePolicy
+	ResolvedImage string
+
+	// PodSecurityAction decides how Pod Security conflicts are handled.
+	PodSecurityAction syncv1beta1.PodSecurityAction
+
+	// QuotaAction decides how LimitRange and ResourceQuota conflicts are
+	// handled.
+	QuotaAction syncv1beta1.QuotaAction
+
+	// ReadinessGate gates pod readiness on clock synchronization.
+	ReadinessGate bool
+
+	// RolloutPercentage is the percentage of pods that receive the sidecar.
+	RolloutPercentage int32
+
+	// Skip and NodeDaemonLabel decide which pods never receive the sidecar.
+	Skip            []syncv1beta1.SkipReason
+	NodeDaemonLabel string
+}
+
+// InjectsPods reports whether the sidecar is added to Pods at admission.
+func (c *Config) InjectsPods() bool {
+	return c.Target == "" || c.Target == syncv1beta1.InjectionTargetPods ||
+		c.Target == syncv1beta1.InjectionTargetPodsAndWorkloads
+}
+
+// InjectsWorkloads reports whether the sidecar is added to workload templates.
+func (c *Config) InjectsWorkloads() bool {
+	return c.Target == syncv1beta1.InjectionTargetWorkloads ||
+		c.Target == syncv1beta1.InjectionTargetPodsAndWorkloads
+}
+
+// Requires reports whether pods must be admitted with the sidecar.
+func (c *Config) Requires() bool {
+	return c.Enforcement == syncv1beta1.EnforcementRequire && !c.Audits() && c.InjectsPods()
+}
+
+// Decision is the result of resolving policies for a pod.
+type Decision struct {
+	// Config is nil when no sidecar should be injected.
+	Config *Config
+	Trace  []Step
+
+	// Warnings are recorded on the object when the sidecar is injected.
+	Warnings []string
+	// Refusal is set when the object must be rejected rather than admitted
+	// without the settings its policy requires.
+	Refusal error
+	// Skipped is why the selected policy does not inject the pod, if it
+	// matched one of its skip rules.
+	Skipped syncv1beta1.SkipReason
+	// Exception is the TimeSyncException that exempts the pod from the
+	// selected policy, if any.
+	Exception string
+}
+
+// Inject reports whether the decision calls for a sidecar.
+func (d Decision) Inject() bool {
+	return d.Config != nil
+}
+
+// Matcher is the compiled form of a policy's selectors and exclusions.
+type Matcher struct {
+	namespaces        labels.Selector
+	pods              labels.Selector
+	excludeNamespaces sets.Set[string]
+	excludePods       []string
+	conditions        []matchCondition
+}
+
+// Compile builds a Matcher for the policy.
+func Compile(p *syncv1beta1.TimeSyncPolicy) (*Matcher, error) {
+	namespaces, err := metav1.LabelSelectorAsSelector(&p.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+	pods := labels.Everything()
+	if p.Spec.PodSelector != nil {
+		if pods, err = metav1.LabelSelectorAsSelector(p.Spec.PodSelector); err != nil {
+			return nil, fmt.Errorf("invalid podSelector: %w", err)
+		}
+	}
+	conditions, err := compileConditions(p.Spec.MatchConditions)
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{
+		namespaces:        namespaces,
+		pods:              pods,
+		excludeNamespaces: sets.New(p.Spec.ExcludeNamespaces...),
+		excludePods:       p.Spec.ExcludePods,
+		conditions:        conditions,
+	}, nil
+}
+
+// NamespaceSelector returns the label selector of the policy's namespaces.
+// Namespaces it lists still need MatchesNamespace for exclusions.
+func (m *Matcher) NamespaceSelector() labels.Selector {
+	return m.namespaces
+}
+
+// PodSelector returns the label selector of the policy's pods. Pods it lists
+// still need MatchesPod for exclusions.
+func (m *Matcher) PodSelector() labels.Selector {
+	return m.pods
+}
+
+// MatchesNamespace reports whether the namespace is selected and not excluded.
+func (m *Matcher) MatchesNamespace(ns *corev1.Namespace) bool {
+	return !m.excludeNamespaces.Has(ns.Name) && m.namespaces.Matches(labels.Set(ns.Labels))
+}
+
+// MatchesPod reports whether the pod is selected and not excluded. It does
+// not look at the pod's namespace; use MatchesNamespace for that.
+func (m *Matcher) MatchesPod(pod *corev1.Pod) bool {
+	return m.pods.Matches(labels.Set(pod.Labels)) && !m.excludesPod(pod)
+}
+
+func (m *Matcher) excludesPod(pod *corev1.Pod) bool {
+	name := pod.Name
+	if name == "" {
+		name = pod.GenerateName
+	}
+	for _, pattern := range m.excludePods {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = pod.Namespace + "/" + name
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(target, prefix) {
+				return true
+			}
+		} else if target == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesNamespace reports whether the policy selects the namespace,
+// regardless of whether the policy is enabled or of its pod selector.
+func MatchesNamespace(p *syncv1beta1.TimeSyncPolicy, ns *corev1.Namespace) (bool, error) {
+	m, err := Compile(p)
+	if err != nil {
+		return false, err
+	}
+	return m.MatchesNamespace(ns), nil
+}
+
+// HasSidecar reports whether the pod already carries the timesync container.
+func HasSidecar(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == SidecarName {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve evaluates the policies against a namespace and a pod. Policies are
+// considered by descending priority, then by name, and the first enabled match
+// wins, so the result does not depend on the order in which they were listed.
+func Resolve(policies []syncv1beta1.TimeSyncPolicy, ns *corev1.Namespace, pod *corev1.Pod) Decision {
+	return ResolveFor(policies, ns, pod, nil)
+}
+
+// ResolveFor is Resolve for a pod admitted on behalf of user, whom match
+// conditions may look at.
+func ResolveFor(policies []syncv1beta1.TimeSyncPolicy, ns *corev1.Namespace, pod *corev1.Pod, user *authenticationv1.UserInfo) Decision {
+	var d Decision
+	conditions := &conditionEvaluator{ns: ns, pod: pod, user: user}
+
+	if pod != nil && HasSidecar(pod) {
+		d.Trace = append(d.Trace, Step{Outcome: OutcomeSidecarPresent, Message: "pod already has a timesync container"})
+		return d
+	}
+
+	for _, p := range sorted(policies) {
+		m, err := Compile(&p)
+		switch {
+		case err != nil:
+			d.Trace = append(d.Trace, Step{Policy: p.Name, Outcome: OutcomeInvalid, Message: err.Error()})
+		case !m.MatchesNamespace(ns):
+			d.Trace = append(d.Trace, Step{Policy: p.Name, Outcome: OutcomeNoMatch,
+				Message: fmt.Sprintf("namespace %q not selected", ns.Name)})
+		case pod != nil && !m.MatchesPod(pod):
+			d.Trace = append(d.Trace, Step{Policy: p.Name, Outcome: OutcomeNoMatch,
+				Message: "pod not selected"})
+		case pod != nil && !conditions.hold(m):
+			d.Trace = append(d.Trace, Step{Policy: p.Name, Outcome: OutcomeNoMatch, Message: conditions.reason})
+		case !p.Spec.Enable:
+			d.Trace = append(d.Trace, Step{Policy: p.Name, Outcome: OutcomeDisabled, Message: "policy is disabled"})
+		case d.Config != nil:
+			d.Trace = append(d.Trace, Step{Policy: p.Name, Outcome: OutcomeShadowed,
+				Message: fmt.Sprintf("policy %q was selected first", d.Config.PolicyName)})
+		default:
+			var pullSecrets []string
+			for _, s := range p.Spec.ImagePullSecrets {
+				pullSecrets = append(pullSecrets, s.Name)
+			}
+			d.Config = &Config{
+				PolicyName:        p.Name,
+				Revision:          RevisionHash(&p.Spec),
+				Mode:              p.Spec.Mode,
+				Enforcement:       p.Spec.Enforcement,
+				Template:          *p.Spec.Template.DeepCopy(),
+				Backend:           p.Spec.Backend,
+				Target:            p.Spec.InjectionTarget,
+				AllowedOverrides:  p.Spec.AllowedOverrides,
+				ClockAdjustment:   p.Spec.ClockAdjustment,
+				ImagePullSecrets:  pullSecrets,
+				ImagePolicy:       p.Spec.ImagePolicy,
+				ResolvedImage:     p.Status.ResolvedImage,
+				PodSecurityAction: p.Spec.PodSecurityAction,
+				QuotaAction:       p.Spec.QuotaAction,
+				ReadinessGate:     p.Spec.ReadinessGate,
+				RolloutPercentage: RolloutPercentage(&p),
+				Skip:              p.Spec.Skip,
+				NodeDaemonLabel:   p.Spec.NodeDaemonLabel,
+			}
+			d.Trace = append(d.Trace, Step{Policy: p.Name, Outcome: OutcomeSelected,
+				Message: fmt.Sprintf("namespace %q selected", ns.Name)})
+		}
+	}
+	return d
+}
+
+func sorted(policies []syncv1beta1.TimeSyncPolicy) []syncv1beta1.TimeSyncPolicy {
+	out := make([]syncv1beta1.TimeSyncPolicy, len(policies))
+	copy(out, policies)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Spec.Priority != out[j].Spec.Priority {
+			return out[i].Spec.Priority > out[j].Spec.Priority
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func newPolicy(name string, enable bool, image string, matchLabels map[string]string) syncv1beta1.TimeSyncPolicy {
+	return syncv1beta1.TimeSyncPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: syncv1beta1.TimeSyncPolicySpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: matchLabels},
+			Enable:            enable,
+			Template:          syncv1beta1.SidecarTemplate{Image: image},
+		},
+	}
+}
+
+func newNamespace(name string, nsLabels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nsLabels}}
+}
+
+func newPod(namespace, name string, podLabels map[string]string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: podLabels}}
+}
+
+func withPodSelector(p syncv1beta1.TimeSyncPolicy, matchLabels map[string]string) syncv1beta1.TimeSyncPolicy {
+	p.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: matchLabels}
+	return p
+}
+
+func invalidPolicy(name string) syncv1beta1.TimeSyncPolicy {
+	p := newPolicy(name, true, "bad:latest", nil)
+	p.Spec.NamespaceSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+		{Key: "env", Operator: "Bogus"},
+	}
+	return p
+}
+
+func TestResolve(t *testing.T) {
+	env := map[string]string{"env": "test"}
+
+	tests := []struct {
+		name       string
+		policies   []syncv1beta1.TimeSyncPolicy
+		ns         *corev1.Namespace
+		pod        *corev1.Pod
+		wantPolicy string
+		wantImage  string
+		wantTrace  []Outcome
+	}{
+		{
+			name:      "no policies",
+			ns:        newNamespace("ns", env),
+			wantTrace: nil,
+		},
+		{
+			name:       "single matching policy",
+			policies:   []syncv1beta1.TimeSyncPolicy{newPolicy("a", true, "img:1", env)},
+			ns:         newNamespace("ns", env),
+			wantPolicy: "a",
+			wantImage:  "img:1",
+			wantTrace:  []Outcome{OutcomeSelected},
+		},
+		{
+			name:      "selector does not match",
+			policies:  []syncv1beta1.TimeSyncPolicy{newPolicy("a", true, "img:1", env)},
+			ns:        newNamespace("ns", map[string]string{"env": "prod"}),
+			wantTrace: []Outcome{OutcomeNoMatch},
+		},
+		{
+			name:      "disabled policy",
+			policies:  []syncv1beta1.TimeSyncPolicy{newPolicy("a", false, "img:1", env)},
+			ns:        newNamespace("ns", env),
+			wantTrace: []Outcome{OutcomeDisabled},
+		},
+		{
+			name:       "empty selector matches every namespace",
+			policies:   []syncv1beta1.TimeSyncPolicy{newPolicy("a", true, "img:1", nil)},
+			ns:         newNamespace("ns", nil),
+			wantPolicy: "a",
+			wantImage:  "img:1",
+			wantTrace:  []Outcome{OutcomeSelected},
+		},
+		{
+			name: "first policy by name wins regardless of list order",
+			policies: []syncv1beta1.TimeSyncPolicy{
+				newPolicy("b", true, "img:b", env),
+				newPolicy("a", true, "img:a", env),
+			},
+			ns:         newNamespace("ns", env),
+			wantPolicy: "a",
+			wantImage:  "img:a",
+			wantTrace:  []Outcome{OutcomeSelected, OutcomeShadowed},
+		},
+		{
+			name: "higher priority wins over name order",
+			policies: []syncv1beta1.TimeSyncPolicy{
+				newPolicy("a", true, "img:a", env),
+				func() syncv1beta1.TimeSyncPolicy {
+					p := newPolicy("b", true, "img:b", env)
+					p.Spec.Priority = 10
+					return p
+				}(),
+			},
+			ns:         newNamespace("ns", env),
+			wantPolicy: "b",
+			wantImage:  "img:b",
+			wantTrace:  []Outcome{OutcomeSelected, OutcomeShadowed},
+		},
+		{
+			name: "disabled policy does not shadow a later one",
+			policies: []syncv1beta1.TimeSyncPolicy{
+				newPolicy("a", false, "img:a", env),
+				newPolicy("b", true, "img:b", env),
+			},
+			ns:         newNamespace("ns", env),
+			wantPolicy: "b",
+			wantImage:  "img:b",
+			wantTrace:  []Outcome{OutcomeDisabled, OutcomeSelected},
+		},
+		{
+			name: "invalid selector is skipped",
+			policies: []syncv1beta1.TimeSyncPolicy{
+				invalidPolicy("a"),
+				newPolicy("b", true, "img:b", env),
+			},
+			ns:         newNamespace("ns", env),
+			wantPolicy: "b",
+			wantImage:  "img:b",
+			wantTrace:  []Outcome{OutcomeInvalid, OutcomeSelected},
+		},
+		{
+			name:     "pod already has the sidecar",
+			policies: []syncv1beta1.TimeSyncPolicy{newPolicy("a", true, "img:1", env)},
+			ns:       newNamespace("ns", env),
+			pod: &corev1.Pod{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: SidecarName}},
+			}},
+			wantTrace: []Outcome{OutcomeSidecarPresent},
+		},
+		{
+			name: "pod selector matches",
+			policies: []syncv1beta1.TimeSyncPolicy{withPodSelector(newPolicy("a", true, "img:1", nil),
+				map[string]string{"app.kubernetes.io/component": "database"})},
+			ns:         newNamespace("ns", nil),
+			pod:        newPod("ns", "db-0", map[string]string{"app.kubernetes.io/component": "database"}),
+			wantPolicy: "a",
+			wantImage:  "img:1",
+			wantTrace:  []Outcome{OutcomeSelected},
+		},
+		{
+			name: "pod selector does not match",
+			policies: []syncv1beta1.TimeSyncPolicy{withPodSelector(newPolicy("a", true, "img:1", nil),
+				map[string]string{"app.kubernetes.io/component": "database"})},
+			ns:        newNamespace("ns", nil),
+			pod:       newPod("ns", "web-0", map[string]string{"app.kubernetes.io/component": "web"}),
+			wantTrace: []Outcome{OutcomeNoMatch},
+		},
+		{
+			name: "excluded namespace",
+			policies: []syncv1beta1.TimeSyncPolicy{func() syncv1beta1.TimeSyncPolicy {
+				p := newPolicy("a", true, "img:1", nil)
+				p.Spec.ExcludeNamespaces = []string{"kube-system"}
+				return p
+			}()},
+			ns:        newNamespace("kube-system", nil),
+			wantTrace: []Outcome{OutcomeNoMatch},
+		},
+		{
+			name: "excluded pod",
+			policies: []syncv1beta1.TimeSyncPolicy{func() syncv1beta1.TimeSyncPolicy {
+				p := newPolicy("a", true, "img:1", nil)
+				p.Spec.ExcludePods = []string{"ns/legacy-*"}
+				return p
+			}()},
+			ns:        newNamespace("ns", nil),
+			pod:       newPod("ns", "legacy-7f9c", nil),
+			wantTrace: []Outcome{OutcomeNoMatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Resolve(tt.policies, tt.ns, tt.pod)
+
+			if tt.wantPolicy == "" {
+				if d.Inject() {
+					t.Fatalf("expected no injection, got policy %q", d.Config.PolicyName)
+				}
+			} else {
+				if !d.Inject() {
+					t.Fatalf("expected injection from %q, got none; trace %v", tt.wantPolicy, d.Trace)
+				}
+				if d.Config.PolicyName != tt.wantPolicy || d.Config.Template.Image != tt.wantImage {
+					t.Fatalf("got %+v, want policy %q image %q", *d.Config, tt.wantPolicy, tt.wantImage)
+				}
+			}
+
+			if len(d.Trace) != len(tt.wantTrace) {
+				t.Fatalf("got trace %v, want outcomes %v", d.Trace, tt.wantTrace)
+			}
+			for i, step := range d.Trace {
+				if step.Outcome != tt.wantTrace[i] {
+					t.Errorf("step %d: got %s, want %s", i, step.Outcome, tt.wantTrace[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesNamespace(t *testing.T) {
+	p := newPolicy("a", false, "img:1", map[string]string{"env": "test"})
+
+	matched, err := MatchesNamespace(&p, newNamespace("ns", map[string]string{"env": "test"}))
+	if err != nil || !matched {
+		t.Fatalf("expected disabled policy to still match, got %v, %v", matched, err)
+	}
+
+	bad := invalidPolicy("bad")
+	if _, err := MatchesNamespace(&bad, newNamespace("ns", nil)); err == nil {
+		t.Fatal("expected an error for an invalid selector")
+	}
+}
+
+func TestMatcherExcludePods(t *testing.T) {
+	p := newPolicy("a", true, "img:1", nil)
+	p.Spec.ExcludePods = []string{"exact", "batch-*", "other/scoped"}
+	m, err := Compile(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pod  *corev1.Pod
+		want bool
+	}{
+		{newPod("ns", "exact", nil), false},
+		{newPod("ns", "exactly", nil), true},
+		{newPod("ns", "batch-1234", nil), false},
+		{&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", GenerateName: "batch-"}}, false},
+		{newPod("ns", "scoped", nil), true},
+		{newPod("other", "scoped", nil), false},
+	}
+	for _, tt := range tests {
+		if got := m.MatchesPod(tt.pod); got != tt.want {
+			t.Errorf("%s/%s: got %v, want %v", tt.pod.Namespace, tt.pod.Name, got, tt.want)
+		}
+	}
+}
+
+func TestCompileInvalidPodSelector(t *testing.T) {
+	p := newPolicy("a", true, "img:1", nil)
+	p.Spec.PodSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "app", Operator: "Bogus"},
+	}}
+	if _, err := Compile(&p); err == nil {
+		t.Fatal("expected an error for an invalid podSelector")
+	}
+}
+
+// FuzzResolve checks that the webhook decision and the controller's namespace
+// matching agree for arbitrary labels and selectors.
+func FuzzResolve(f *testing.F) {
+	f.Add("env", "test", "env", "test", "In", true)
+	f.Add("env", "prod", "env", "test", "NotIn", true)
+	f.Add("team", "", "env", "", "Exists", false)
+	f.Add("", "", "k", "v", "Bogus", true)
+
+	f.Fuzz(func(t *testing.T, nsKey, nsValue, selKey, selValue, op string, enable bool) {
+		ns := newNamespace("ns", map[string]string{nsKey: nsValue})
+		p := newPolicy("fuzz", enable, "img:1", nil)
+		p.Spec.NamespaceSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+			Key:      selKey,
+			Operator: metav1.LabelSelectorOperator(op),
+			Values:   []string{selValue},
+		}}
+
+		d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, ns, nil)
+		matched, err := MatchesNamespace(&p, ns)
+
+		if len(d.Trace) != 1 {
+			t.Fatalf("expected exactly one trace step, got %v", d.Trace)
+		}
+		if err != nil {
+			if d.Inject() || d.Trace[0].Outcome != OutcomeInvalid {
+				t.Fatalf("invalid selector must not inject: %v", d.Trace)
+			}
+			return
+		}
+		if d.Inject() != (matched && enable) {
+			t.Fatalf("decision %v disagrees with match=%v enable=%v", d.Trace, matched, enable)
+		}
+	})
+}
+
+func TestConfigTargets(t *testing.T) {
+	tests := []struct {
+		target         syncv1beta1.InjectionTarget
+		pods, workload bool
+	}{
+		{"", true, false},
+		{syncv1beta1.InjectionTargetPods, true, false},
+		{syncv1beta1.InjectionTargetWorkloads, false, true},
+		{syncv1beta1.InjectionTargetPodsAndWorkloads, true, true},
+	}
+	for _, tt := range tests {
+		c := &Config{Target: tt.target}
+		if c.InjectsPods() != tt.pods || c.InjectsWorkloads() != tt.workload {
+			t.Errorf("target %q: got pods=%v workloads=%v, want %v %v",
+				tt.target, c.InjectsPods(), c.InjectsWorkloads(), tt.pods, tt.workload)
+		}
+	}
+}
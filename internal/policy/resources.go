@@ -0,0 +1,252 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// DefaultResources returns the sidecar resources used when the template sets
+// none.
+func DefaultResources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("16Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+		},
+	}
+}
+
+// quotaResources are the quota keys a pod is charged for and must set, as
+// the request or limit they apply to.
+var quotaResources = map[corev1.ResourceName]struct {
+	name  corev1.ResourceName
+	limit bool
+}{
+	corev1.ResourceCPU:            {name: corev1.ResourceCPU},
+	corev1.ResourceMemory:         {name: corev1.ResourceMemory},
+	corev1.ResourceRequestsCPU:    {name: corev1.ResourceCPU},
+	corev1.ResourceRequestsMemory: {name: corev1.ResourceMemory},
+	corev1.ResourceLimitsCPU:      {name: corev1.ResourceCPU, limit: true},
+	corev1.ResourceLimitsMemory:   {name: corev1.ResourceMemory, limit: true},
+}
+
+// ApplyResources gives the sidecar default resources, adjusted to the
+// namespace's LimitRanges, when the template sets none. It then checks that
+// the sidecar respects the LimitRanges and does not push the pod described
+// by spec over a ResourceQuota. Problems are returned as warnings, or the
+// decision is refused if the policy asks for that.
+func ApplyResources(
+	d Decision,
+	spec *corev1.PodSpec,
+	limitRanges []corev1.LimitRange,
+	quotas []corev1.ResourceQuota,
+) Decision {
+	if d.Config == nil {
+		return d
+	}
+
+	cfg := *d.Config
+	if len(cfg.Template.Resources.Requests) == 0 && len(cfg.Template.Resources.Limits) == 0 {
+		cfg.Template.Resources = clampToLimitRanges(DefaultResources(), limitRanges)
+	}
+	d.Config = &cfg
+
+	problems := limitRangeViolations(cfg.Template.Resources, limitRanges)
+	if spec != nil {
+		problems = append(problems, quotaViolations(spec, cfg.Template.Resources, limitRanges, quotas)...)
+	}
+	if len(problems) == 0 {
+		return d
+	}
+
+	if cfg.QuotaAction == syncv1beta1.QuotaActionRefuse {
+		d.Refusal = errors.New(strings.Join(problems, "; "))
+		d.Trace = append(d.Trace, Step{Policy: cfg.PolicyName, Outcome: OutcomeRefused, Message: d.Refusal.Error()})
+		return d
+	}
+	for _, p := range problems {
+		d.Warnings = append(d.Warnings, p)
+		d.Trace = append(d.Trace, Step{Policy: cfg.PolicyName, Outcome: OutcomeOverQuota, Message: p})
+	}
+	return d
+}
+
+// clampToLimitRanges moves the requests and limits into the container
+// bounds of the LimitRanges, keeping every request within its limit.
+func clampToLimitRanges(r corev1.ResourceRequirements, limitRanges []corev1.LimitRange) corev1.ResourceRequirements {
+	clamp := func(list corev1.ResourceList, name corev1.ResourceName, bound resource.Quantity, below bool) {
+		if v, ok := list[name]; ok && (below && v.Cmp(bound) < 0 || !below && v.Cmp(bound) > 0) {
+			list[name] = bound.DeepCopy()
+		}
+	}
+	for _, item := range containerLimits(limitRanges) {
+		for name, limit := range item.Min {
+			clamp(r.Requests, name, limit, true)
+			clamp(r.Limits, name, limit, true)
+		}
+		for name, limit := range item.Max {
+			clamp(r.Requests, name, limit, false)
+			clamp(r.Limits, name, limit, false)
+		}
+	}
+	for name, limit := range r.Limits {
+		clamp(r.Requests, name, limit, false)
+	}
+	return r
+}
+
+// limitRangeViolations lists the ways the sidecar resources break the
+// container bounds of the LimitRanges.
+func limitRangeViolations(r corev1.ResourceRequirements, limitRanges []corev1.LimitRange) []string {
+	var problems []string
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for _, name := range sortedNames(item.Min) {
+				min := item.Min[name]
+				if v, ok := r.Requests[name]; ok && v.Cmp(min) < 0 {
+					problems = append(problems, fmt.Sprintf("sidecar %s request %s is below the minimum %s of LimitRange %s",
+						name, v.String(), min.String(), lr.Name))
+				}
+			}
+			for _, name := range sortedNames(item.Max) {
+				max := item.Max[name]
+				if v, ok := r.Limits[name]; ok && v.Cmp(max) > 0 {
+					problems = append(problems, fmt.Sprintf("sidecar %s limit %s is above the maximum %s of LimitRange %s",
+						name, v.String(), max.String(), lr.Name))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// quotaViolations lists the ResourceQuotas that admit the pod without the
+// sidecar but not with it, or that the sidecar cannot be charged against.
+func quotaViolations(
+	spec *corev1.PodSpec,
+	sidecar corev1.ResourceRequirements,
+	limitRanges []corev1.LimitRange,
+	quotas []corev1.ResourceQuota,
+) []string {
+	var problems []string
+	for _, q := range quotas {
+		for _, key := range sortedNames(q.Spec.Hard) {
+			if _, ok := quotaResources[key]; !ok {
+				continue
+			}
+			extra, ok := usage(sidecar, key, limitRanges)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("ResourceQuota %s requires %s, which the sidecar does not set",
+					q.Name, key))
+				continue
+			}
+			remaining := q.Spec.Hard[key].DeepCopy()
+			if used, ok := q.Status.Used[key]; ok {
+				remaining.Sub(used)
+			}
+			before := podUsage(spec, key, limitRanges)
+			after := before.DeepCopy()
+			after.Add(extra)
+			if before.Cmp(remaining) <= 0 && after.Cmp(remaining) > 0 {
+				problems = append(problems, fmt.Sprintf(
+					"the sidecar pushes the pod over ResourceQuota %s for %s: %s remaining, %s needed with the sidecar",
+					q.Name, key, remaining.String(), after.String()))
+			}
+		}
+	}
+	return problems
+}
+
+// podUsage returns what the pod is charged for the quota key: the sum over
+// its containers, or its largest init container if that is more.
+func podUsage(spec *corev1.PodSpec, key corev1.ResourceName, limitRanges []corev1.LimitRange) resource.Quantity {
+	var total resource.Quantity
+	for _, c := range spec.Containers {
+		if v, ok := usage(c.Resources, key, limitRanges); ok {
+			total.Add(v)
+		}
+	}
+	for _, c := range spec.InitContainers {
+		if v, ok := usage(c.Resources, key, limitRanges); ok && v.Cmp(total) > 0 {
+			total = v
+		}
+	}
+	return total
+}
+
+// usage returns the amount a container is charged for the quota key once
+// the LimitRange defaults are applied, and whether it sets one at all.
+func usage(r corev1.ResourceRequirements, key corev1.ResourceName, limitRanges []corev1.LimitRange) (resource.Quantity, bool) {
+	q := quotaResources[key]
+	limit, hasLimit := r.Limits[q.name]
+	if !hasLimit {
+		for _, item := range containerLimits(limitRanges) {
+			if limit, hasLimit = item.Default[q.name]; hasLimit {
+				break
+			}
+		}
+	}
+	if q.limit {
+		return limit, hasLimit
+	}
+	if v, ok := r.Requests[q.name]; ok {
+		return v, true
+	}
+	for _, item := range containerLimits(limitRanges) {
+		if v, ok := item.DefaultRequest[q.name]; ok {
+			return v, true
+		}
+	}
+	// The API server defaults a missing request to the limit.
+	return limit, hasLimit
+}
+
+func containerLimits(limitRanges []corev1.LimitRange) []corev1.LimitRangeItem {
+	var items []corev1.LimitRangeItem
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type == corev1.LimitTypeContainer {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func sortedNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
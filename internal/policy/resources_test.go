@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func newLimitRange(item corev1.LimitRangeItem) corev1.LimitRange {
+	item.Type = corev1.LimitTypeContainer
+	return corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "ns"},
+		Spec:       corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{item}},
+	}
+}
+
+func newQuota(hard, used corev1.ResourceList) corev1.ResourceQuota {
+	return corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "ns"},
+		Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func resources(cpu, memory string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	if cpu != "" {
+		list[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		list[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return list
+}
+
+func TestApplyResourcesDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    corev1.ResourceRequirements
+		limitRanges []corev1.LimitRange
+		want        corev1.ResourceRequirements
+	}{
+		{
+			name: "defaults without limit ranges",
+			want: DefaultResources(),
+		},
+		{
+			name:     "explicit resources are kept",
+			template: corev1.ResourceRequirements{Requests: resources("50m", "")},
+			want:     corev1.ResourceRequirements{Requests: resources("50m", "")},
+		},
+		{
+			name:        "defaults raised to the minimum",
+			limitRanges: []corev1.LimitRange{newLimitRange(corev1.LimitRangeItem{Min: resources("20m", "64Mi")})},
+			want: corev1.ResourceRequirements{
+				Requests: resources("20m", "64Mi"),
+				Limits:   resources("", "64Mi"),
+			},
+		},
+		{
+			name:        "defaults lowered to the maximum",
+			limitRanges: []corev1.LimitRange{newLimitRange(corev1.LimitRangeItem{Max: resources("5m", "8Mi")})},
+			want: corev1.ResourceRequirements{
+				Requests: resources("5m", "8Mi"),
+				Limits:   resources("", "8Mi"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("cluster", true, "img", nil)
+			p.Spec.Template.Resources = tt.template
+			d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil)
+			d = ApplyResources(d, &corev1.PodSpec{}, tt.limitRanges, nil)
+
+			got := d.Config.Template.Resources
+			for _, list := range []struct{ got, want corev1.ResourceList }{
+				{got.Requests, tt.want.Requests},
+				{got.Limits, tt.want.Limits},
+			} {
+				if len(list.got) != len(list.want) {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+				for name, want := range list.want {
+					if v := list.got[name]; v.Cmp(want) != 0 {
+						t.Errorf("got %s %s, want %s", name, v.String(), want.String())
+					}
+				}
+			}
+			if len(d.Warnings) != 0 {
+				t.Errorf("got warnings %v", d.Warnings)
+			}
+		})
+	}
+}
+
+func TestApplyResourcesQuota(t *testing.T) {
+	pod := &corev1.PodSpec{Containers: []corev1.Container{{
+		Name:      "app",
+		Resources: corev1.ResourceRequirements{Requests: resources("100m", "100Mi"), Limits: resources("200m", "100Mi")},
+	}}}
+
+	tests := []struct {
+		name        string
+		action      syncv1beta1.QuotaAction
+		template    corev1.ResourceRequirements
+		limitRanges []corev1.LimitRange
+		quotas      []corev1.ResourceQuota
+		wantTrace   []Outcome
+		wantRefusal bool
+	}{
+		{
+			name:      "no quota",
+			wantTrace: []Outcome{OutcomeSelected},
+		},
+		{
+			name: "sidecar fits",
+			quotas: []corev1.ResourceQuota{newQuota(
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("1Gi")},
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("512Mi")},
+			)},
+			wantTrace: []Outcome{OutcomeSelected},
+		},
+		{
+			name: "sidecar pushes the pod over the quota",
+			quotas: []corev1.ResourceQuota{newQuota(
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("1Gi")},
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("910Mi")},
+			)},
+			wantTrace: []Outcome{OutcomeSelected, OutcomeOverQuota},
+		},
+		{
+			name:   "refused when the policy asks",
+			action: syncv1beta1.QuotaActionRefuse,
+			quotas: []corev1.ResourceQuota{newQuota(
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("1Gi")},
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("910Mi")},
+			)},
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeRefused},
+			wantRefusal: true,
+		},
+		{
+			name: "pod already over the quota",
+			quotas: []corev1.ResourceQuota{newQuota(
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("1Gi")},
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("1Gi")},
+			)},
+			wantTrace: []Outcome{OutcomeSelected},
+		},
+		{
+			name: "quota requires a limit the sidecar does not set",
+			quotas: []corev1.ResourceQuota{newQuota(
+				corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("10")}, nil,
+			)},
+			wantTrace: []Outcome{OutcomeSelected, OutcomeOverQuota},
+		},
+		{
+			name:        "limit range default satisfies the quota",
+			limitRanges: []corev1.LimitRange{newLimitRange(corev1.LimitRangeItem{Default: resources("50m", "")})},
+			quotas: []corev1.ResourceQuota{newQuota(
+				corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("10")}, nil,
+			)},
+			wantTrace: []Outcome{OutcomeSelected},
+		},
+		{
+			name:        "explicit limit above the limit range maximum",
+			template:    corev1.ResourceRequirements{Limits: resources("", "32Mi")},
+			limitRanges: []corev1.LimitRange{newLimitRange(corev1.LimitRangeItem{Max: resources("", "16Mi")})},
+			wantTrace:   []Outcome{OutcomeSelected, OutcomeOverQuota},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("cluster", true, "img", nil)
+			p.Spec.QuotaAction = tt.action
+			p.Spec.Template.Resources = tt.template
+			d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil)
+			d = ApplyResources(d, pod, tt.limitRanges, tt.quotas)
+
+			if (d.Refusal != nil) != tt.wantRefusal {
+				t.Fatalf("got refusal %v, want %v", d.Refusal, tt.wantRefusal)
+			}
+			if len(d.Trace) != len(tt.wantTrace) {
+				t.Fatalf("got trace %v, want outcomes %v", d.Trace, tt.wantTrace)
+			}
+			for i, step := range d.Trace {
+				if step.Outcome != tt.wantTrace[i] {
+					t.Errorf("step %d: got %s, want %s", i, step.Outcome, tt.wantTrace[i])
+				}
+			}
+			if want := len(tt.wantTrace) - 1; !tt.wantRefusal && len(d.Warnings) != want {
+				t.Errorf("got warnings %v, want %d", d.Warnings, want)
+			}
+		})
+	}
+}
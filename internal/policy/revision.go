@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/dump"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// RevisionSpec returns a copy of spec without the fields that manage its
+// revision history, which is what a revision stores.
+func RevisionSpec(spec *syncv1beta1.TimeSyncPolicySpec) *syncv1beta1.TimeSyncPolicySpec {
+	out := spec.DeepCopy()
+	out.RevisionHistoryLimit = nil
+	out.RollbackTo = nil
+	return out
+}
+
+// RevisionHash identifies the revision of spec, like the pod-template-hash
+// of a ReplicaSet. Changes to revisionHistoryLimit and rollbackTo alone do
+// not make a new revision.
+func RevisionHash(spec *syncv1beta1.TimeSyncPolicySpec) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(dump.ForHash(RevisionSpec(spec))))
+	return rand.SafeEncodeString(strconv.FormatUint(uint64(h.Sum32()), 10))
+}
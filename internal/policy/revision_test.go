@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestRevisionHash(t *testing.T) {
+	p := newPolicy("cluster", true, "img:v1", nil)
+	hash := RevisionHash(&p.Spec)
+
+	p.Spec.RevisionHistoryLimit = ptr.To[int32](3)
+	p.Spec.RollbackTo = ptr.To[int64](1)
+	if got := RevisionHash(&p.Spec); got != hash {
+		t.Errorf("history fields changed the hash from %q to %q", hash, got)
+	}
+
+	p.Spec.Template.Image = "img:v2"
+	if got := RevisionHash(&p.Spec); got == hash {
+		t.Errorf("image change kept the hash %q", hash)
+	}
+
+	p.Spec.Template.Image = "img:v1"
+	d := Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil)
+	if !d.Inject() || d.Config.Revision != hash {
+		t.Errorf("resolved revision %+v, want %q", d.Config, hash)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/dump"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// RolloutPercentage returns the percentage of pods the policy currently
+// injects: all of them without a rollout, the rollout's percentage without
+// steps, and otherwise the step the controller has reached for the sidecar
+// the policy currently injects.
+func RolloutPercentage(p *syncv1beta1.TimeSyncPolicy) int32 {
+	rollout := p.Spec.Rollout
+	switch {
+	case rollout == nil:
+		return 100
+	case len(rollout.Steps) == 0:
+		return rollout.Percentage
+	case p.Status.Rollout != nil && p.Status.Rollout.Hash == RolloutHash(&p.Spec):
+		return p.Status.Rollout.Percentage
+	default:
+		return rollout.Percentage
+	}
+}
+
+// RolloutHash identifies what a rollout rolls out: the sidecar built from the
+// template, backend, clockAdjustment and imagePolicy of spec. Changes to the
+// rest of the spec, the rollout included, do not restart it.
+func RolloutHash(spec *syncv1beta1.TimeSyncPolicySpec) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(dump.ForHash(struct {
+		Template        syncv1beta1.SidecarTemplate
+		Backend         syncv1beta1.Backend
+		ClockAdjustment syncv1beta1.ClockAdjustment
+		ImagePolicy     *syncv1beta1.ImagePolicy
+	}{spec.Template, spec.Backend, spec.ClockAdjustment, spec.ImagePolicy})))
+	return rand.SafeEncodeString(strconv.FormatUint(uint64(h.Sum32()), 10))
+}
+
+// RolloutKey identifies the group of pods a rollout treats alike: the pods of
+// the same top-level owner, or else the pod itself. Pods of a Deployment are
+// keyed by the Deployment rather than their ReplicaSet, so that they get the
+// decision the workload webhook made for its template, and keep it across
+// updates of the Deployment.
+func RolloutKey(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		kind, name := owner.Kind, owner.Name
+		// A Deployment names its ReplicaSets after itself and the hash of
+		// their pod template, which their pods carry as a label.
+		if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; kind == "ReplicaSet" && hash != "" {
+			if deployment, ok := strings.CutSuffix(name, "-"+hash); ok {
+				kind, name = "Deployment", deployment
+			}
+		}
+		return pod.Namespace + "/" + kind + "/" + name
+	}
+	name := pod.Name
+	if name == "" {
+		name = pod.GenerateName
+	}
+	return pod.Namespace + "/Pod/" + name
+}
+
+// InRollout reports whether the pods identified by key fall within the
+// percentage. The choice is stable, so raising the percentage only adds pods.
+func InRollout(key string, percentage int32) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int32(h.Sum32()%100) < percentage
+}
+
+// ApplyRollout withdraws the sidecar from a pod outside the selected policy's
+// rollout.
+func ApplyRollout(d Decision, pod *corev1.Pod) Decision {
+	if d.Config == nil || pod == nil || d.Config.RolloutPercentage >= 100 {
+		return d
+	}
+	if InRollout(RolloutKey(pod), d.Config.RolloutPercentage) {
+		return d
+	}
+	d.Trace = append(d.Trace, Step{Policy: d.Config.PolicyName, Outcome: OutcomeNotRolledOut,
+		Message: fmt.Sprintf("pod is outside the %d%% rollout", d.Config.RolloutPercentage)})
+	d.Config = nil
+	return d
+}
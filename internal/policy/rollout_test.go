@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestRolloutPercentage(t *testing.T) {
+	steps := []syncv1beta1.RolloutStep{{Percentage: 50, After: metav1.Duration{}}}
+	started := newPolicy("p", true, "img", nil)
+	tests := []struct {
+		name    string
+		rollout *syncv1beta1.Rollout
+		edit    func(*syncv1beta1.TimeSyncPolicy)
+		status  *syncv1beta1.RolloutStatus
+		want    int32
+	}{
+		{name: "no rollout", want: 100},
+		{name: "fixed percentage", rollout: &syncv1beta1.Rollout{Percentage: 10}, want: 10},
+		{name: "steps not started", rollout: &syncv1beta1.Rollout{Percentage: 10, Steps: steps}, want: 10},
+		{
+			name:    "steps in progress",
+			rollout: &syncv1beta1.Rollout{Percentage: 10, Steps: steps},
+			status:  &syncv1beta1.RolloutStatus{Hash: RolloutHash(&started.Spec), Step: 1, Percentage: 50},
+			want:    50,
+		},
+		{
+			name:    "other spec changes keep the step",
+			rollout: &syncv1beta1.Rollout{Percentage: 10, Steps: steps},
+			edit:    func(p *syncv1beta1.TimeSyncPolicy) { p.Spec.Priority = 5 },
+			status:  &syncv1beta1.RolloutStatus{Hash: RolloutHash(&started.Spec), Step: 1, Percentage: 50},
+			want:    50,
+		},
+		{
+			name:    "image changed since the rollout started",
+			rollout: &syncv1beta1.Rollout{Percentage: 10, Steps: steps},
+			edit:    func(p *syncv1beta1.TimeSyncPolicy) { p.Spec.Template.Image = "img:v2" },
+			status:  &syncv1beta1.RolloutStatus{Hash: RolloutHash(&started.Spec), Step: 1, Percentage: 50},
+			want:    10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("p", true, "img", nil)
+			p.Spec.Rollout = tt.rollout
+			if tt.edit != nil {
+				tt.edit(&p)
+			}
+			p.Status.Rollout = tt.status
+			if got := RolloutPercentage(&p); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolloutKey(t *testing.T) {
+	pod := newPod("ns", "web-abc12", nil)
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d8f", Controller: ptr.To(true)}}
+	sibling := newPod("ns", "web-def34", nil)
+	sibling.OwnerReferences = pod.OwnerReferences
+	if RolloutKey(pod) != RolloutKey(sibling) {
+		t.Errorf("pods of one ReplicaSet got keys %q and %q", RolloutKey(pod), RolloutKey(sibling))
+	}
+
+	generated := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", GenerateName: "job-"}}
+	if got, want := RolloutKey(generated), "ns/Pod/job-"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRolloutKeyOfDeploymentPods(t *testing.T) {
+	// The workload webhook decides for the template as a pod the Deployment
+	// controls.
+	template := newPod("ns", "", nil)
+	template.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: ptr.To(true)}}
+	pod := newPod("ns", "web-5d8f9c7b4-x2k9p", map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "5d8f9c7b4"})
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d8f9c7b4", Controller: ptr.To(true)}}
+	if got, want := RolloutKey(pod), RolloutKey(template); got != want {
+		t.Fatalf("pod of the Deployment got key %q, its template %q", got, want)
+	}
+
+	p := newPolicy("canary", true, "img", nil)
+	p.Spec.Rollout = &syncv1beta1.Rollout{}
+	for pct := int32(0); pct <= 100; pct += 5 {
+		p.Spec.Rollout.Percentage = pct
+		ns := newNamespace("ns", nil)
+		forTemplate := ApplyRollout(Resolve([]syncv1beta1.TimeSyncPolicy{p}, ns, nil), template)
+		forPod := ApplyRollout(Resolve([]syncv1beta1.TimeSyncPolicy{p}, ns, nil), pod)
+		if forTemplate.Inject() != forPod.Inject() {
+			t.Errorf("%d%% rollout: template inject=%v, pod inject=%v", pct, forTemplate.Inject(), forPod.Inject())
+		}
+	}
+}
+
+func TestInRolloutIsMonotonicAndProportional(t *testing.T) {
+	const keys = 10000
+	for _, pct := range []int32{0, 10, 50, 100} {
+		in := 0
+		for i := range keys {
+			key := fmt.Sprintf("ns/ReplicaSet/app-%d", i)
+			if InRollout(key, pct) {
+				in++
+				if !InRollout(key, pct+10) {
+					t.Fatalf("key %q left the rollout when it grew past %d%%", key, pct)
+				}
+			}
+		}
+		if got := in * 100 / keys; got < int(pct)-3 || got > int(pct)+3 {
+			t.Errorf("%d%% rollout selected %d%% of keys", pct, got)
+		}
+	}
+}
+
+func TestApplyRollout(t *testing.T) {
+	p := newPolicy("canary", true, "img", nil)
+	p.Spec.Rollout = &syncv1beta1.Rollout{Percentage: 0}
+	d := ApplyRollout(Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil), newPod("ns", "web", nil))
+	if d.Inject() {
+		t.Fatalf("0%% rollout injected the pod")
+	}
+	if last := d.Trace[len(d.Trace)-1]; last.Outcome != OutcomeNotRolledOut {
+		t.Errorf("got outcome %s, want %s", last.Outcome, OutcomeNotRolledOut)
+	}
+
+	p.Spec.Rollout.Percentage = 100
+	d = ApplyRollout(Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), nil), newPod("ns", "web", nil))
+	if !d.Inject() {
+		t.Fatalf("100%% rollout did not inject the pod; trace %v", d.Trace)
+	}
+}
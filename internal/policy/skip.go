@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// SkippedAnnotation records on a pod, or a workload, the skip rule that
+// kept its matching policy from injecting the sidecar.
+const SkippedAnnotation = "sync.example.com/skipped"
+
+// SkipReasonFor returns the first of the policy's skip rules that applies to
+// the pod, with a message describing why.
+func SkipReasonFor(cfg *Config, pod *corev1.Pod) (syncv1beta1.SkipReason, string, bool) {
+	for _, reason := range cfg.Skip {
+		if message, ok := skips(reason, cfg, pod); ok {
+			return reason, message, true
+		}
+	}
+	return "", "", false
+}
+
+func skips(reason syncv1beta1.SkipReason, cfg *Config, pod *corev1.Pod) (string, bool) {
+	switch reason {
+	case syncv1beta1.SkipReasonWindows:
+		if pod.Spec.OS != nil && pod.Spec.OS.Name == corev1.Windows {
+			return "pod runs on Windows", true
+		}
+	case syncv1beta1.SkipReasonHostNetwork:
+		if pod.Spec.HostNetwork {
+			return "pod uses the host network", true
+		}
+	case syncv1beta1.SkipReasonMirrorPod:
+		if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+			return "pod mirrors a static pod", true
+		}
+	case syncv1beta1.SkipReasonDaemonSet:
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+			return "pod is owned by a DaemonSet", true
+		}
+	case syncv1beta1.SkipReasonNodeDaemon:
+		if _, ok := pod.Spec.NodeSelector[cfg.NodeDaemonLabel]; ok && cfg.NodeDaemonLabel != "" {
+			return fmt.Sprintf("pod targets nodes labeled %s, which run a node-level time daemon", cfg.NodeDaemonLabel), true
+		}
+	}
+	return "", false
+}
+
+// ApplySkipRules withdraws the sidecar from a pod that matches one of the
+// selected policy's skip rules, recording the reason in the decision.
+func ApplySkipRules(d Decision, pod *corev1.Pod) Decision {
+	if d.Config == nil || pod == nil {
+		return d
+	}
+	reason, message, ok := SkipReasonFor(d.Config, pod)
+	if !ok {
+		return d
+	}
+	d.Trace = append(d.Trace, Step{Policy: d.Config.PolicyName, Outcome: OutcomeSkipped, Message: message})
+	d.Config = nil
+	d.Skipped = reason
+	return d
+}
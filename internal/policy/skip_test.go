@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestApplySkipRules(t *testing.T) {
+	all := []syncv1beta1.SkipReason{
+		syncv1beta1.SkipReasonWindows,
+		syncv1beta1.SkipReasonHostNetwork,
+		syncv1beta1.SkipReasonMirrorPod,
+		syncv1beta1.SkipReasonDaemonSet,
+		syncv1beta1.SkipReasonNodeDaemon,
+	}
+
+	tests := []struct {
+		name   string
+		skip   []syncv1beta1.SkipReason
+		mutate func(*corev1.Pod)
+		want   syncv1beta1.SkipReason
+	}{
+		{
+			name:   "regular pod",
+			skip:   all,
+			mutate: func(*corev1.Pod) {},
+		},
+		{
+			name:   "windows pod",
+			skip:   all,
+			mutate: func(p *corev1.Pod) { p.Spec.OS = &corev1.PodOS{Name: corev1.Windows} },
+			want:   syncv1beta1.SkipReasonWindows,
+		},
+		{
+			name:   "host network pod",
+			skip:   all,
+			mutate: func(p *corev1.Pod) { p.Spec.HostNetwork = true },
+			want:   syncv1beta1.SkipReasonHostNetwork,
+		},
+		{
+			name: "mirror pod",
+			skip: all,
+			mutate: func(p *corev1.Pod) {
+				p.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"}
+			},
+			want: syncv1beta1.SkipReasonMirrorPod,
+		},
+		{
+			name: "daemonset pod",
+			skip: all,
+			mutate: func(p *corev1.Pod) {
+				p.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: ptr.To(true)}}
+			},
+			want: syncv1beta1.SkipReasonDaemonSet,
+		},
+		{
+			name: "pod owned by a replicaset",
+			skip: all,
+			mutate: func(p *corev1.Pod) {
+				p.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", Controller: ptr.To(true)}}
+			},
+		},
+		{
+			name: "pod targeting node daemon nodes",
+			skip: all,
+			mutate: func(p *corev1.Pod) {
+				p.Spec.NodeSelector = map[string]string{"sync.example.com/node-daemon": "true"}
+			},
+			want: syncv1beta1.SkipReasonNodeDaemon,
+		},
+		{
+			name:   "rule not configured",
+			skip:   []syncv1beta1.SkipReason{syncv1beta1.SkipReasonWindows},
+			mutate: func(p *corev1.Pod) { p.Spec.HostNetwork = true },
+		},
+		{
+			name: "first configured rule wins",
+			skip: []syncv1beta1.SkipReason{syncv1beta1.SkipReasonHostNetwork, syncv1beta1.SkipReasonWindows},
+			mutate: func(p *corev1.Pod) {
+				p.Spec.OS = &corev1.PodOS{Name: corev1.Windows}
+				p.Spec.HostNetwork = true
+			},
+			want: syncv1beta1.SkipReasonHostNetwork,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPolicy("cluster", true, "img", nil)
+			p.Spec.Skip = tt.skip
+			p.Spec.NodeDaemonLabel = "sync.example.com/node-daemon"
+			pod := newPod("ns", "pod", nil)
+			tt.mutate(pod)
+
+			d := ApplySkipRules(Resolve([]syncv1beta1.TimeSyncPolicy{p}, newNamespace("ns", nil), pod), pod)
+			if d.Skipped != tt.want {
+				t.Fatalf("got skipped %q, want %q; trace %v", d.Skipped, tt.want, d.Trace)
+			}
+			if d.Inject() != (tt.want == "") {
+				t.Fatalf("got inject=%v with skipped %q", d.Inject(), d.Skipped)
+			}
+			if tt.want != "" && d.Trace[len(d.Trace)-1].Outcome != OutcomeSkipped {
+				t.Errorf("got trace %v, want a final %s step", d.Trace, OutcomeSkipped)
+			}
+		})
+	}
+}
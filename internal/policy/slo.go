@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+// Sidecars and node agents report the clock state of a pod through these
+// annotations: the offset from the reference clock in seconds, and the RFC
+// 3339 time of the last successful synchronization.
+const (
+	OffsetAnnotation   = "sync.example.com/offset-seconds"
+	SyncedAtAnnotation = "sync.example.com/synced-at"
+)
+
+// Compliance is the state of a pod against a ClockSkewSLO.
+type Compliance struct {
+	// Reported is true when the pod has reported a valid offset.
+	Reported bool
+	Offset   time.Duration
+	// Violation explains why the pod is outside the SLO, and is empty when
+	// it complies.
+	Violation string
+}
+
+// CheckSLO compares the clock state the pod reports with the SLO at now. A
+// pod that never reported a synchronization is measured from its start.
+func CheckSLO(slo *syncv1beta1.ClockSkewSLO, pod *corev1.Pod, now time.Time) Compliance {
+	var c Compliance
+	if raw, ok := pod.Annotations[OffsetAnnotation]; ok {
+		if seconds, err := strconv.ParseFloat(raw, 64); err == nil {
+			c.Reported = true
+			c.Offset = time.Duration(seconds * float64(time.Second))
+		}
+	}
+	if slo == nil {
+		return c
+	}
+
+	if slo.MaxOffset != nil && c.Reported && c.Offset.Abs() > slo.MaxOffset.Duration {
+		c.Violation = fmt.Sprintf("offset %s exceeds maxOffset %s", c.Offset, slo.MaxOffset.Duration)
+		return c
+	}
+	if slo.MaxUnsyncedDuration != nil {
+		last := pod.CreationTimestamp.Time
+		if pod.Status.StartTime != nil {
+			last = pod.Status.StartTime.Time
+		}
+		if t, err := time.Parse(time.RFC3339, pod.Annotations[SyncedAtAnnotation]); err == nil {
+			last = t
+		}
+		if unsynced := now.Sub(last); unsynced > slo.MaxUnsyncedDuration.Duration {
+			c.Violation = fmt.Sprintf("not synchronized for %s, longer than maxUnsyncedDuration %s",
+				unsynced.Truncate(time.Second), slo.MaxUnsyncedDuration.Duration)
+		}
+	}
+	return c
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestCheckSLO(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	slo := &syncv1beta1.ClockSkewSLO{
+		MaxOffset:           &metav1.Duration{Duration: 100 * time.Millisecond},
+		MaxUnsyncedDuration: &metav1.Duration{Duration: 10 * time.Minute},
+	}
+
+	tests := []struct {
+		name         string
+		slo          *syncv1beta1.ClockSkewSLO
+		annotations  map[string]string
+		created      time.Time
+		wantReported bool
+		wantOffset   time.Duration
+		wantViolated bool
+	}{
+		{
+			name:         "within the SLO",
+			slo:          slo,
+			annotations:  map[string]string{OffsetAnnotation: "0.05", SyncedAtAnnotation: "2025-01-01T11:59:00Z"},
+			created:      now.Add(-time.Hour),
+			wantReported: true,
+			wantOffset:   50 * time.Millisecond,
+		},
+		{
+			name:         "negative offset beyond maxOffset",
+			slo:          slo,
+			annotations:  map[string]string{OffsetAnnotation: "-0.25", SyncedAtAnnotation: "2025-01-01T11:59:00Z"},
+			created:      now.Add(-time.Hour),
+			wantReported: true,
+			wantOffset:   -250 * time.Millisecond,
+			wantViolated: true,
+		},
+		{
+			name:         "last sync too long ago",
+			slo:          slo,
+			annotations:  map[string]string{OffsetAnnotation: "0", SyncedAtAnnotation: "2025-01-01T11:00:00Z"},
+			created:      now.Add(-time.Hour),
+			wantReported: true,
+			wantViolated: true,
+		},
+		{
+			name:    "new pod that has not reported yet",
+			slo:     slo,
+			created: now.Add(-time.Minute),
+		},
+		{
+			name:         "pod that never reported",
+			slo:          slo,
+			created:      now.Add(-time.Hour),
+			wantViolated: true,
+		},
+		{
+			name:        "malformed offset is ignored",
+			slo:         slo,
+			annotations: map[string]string{OffsetAnnotation: "fast", SyncedAtAnnotation: "2025-01-01T11:59:00Z"},
+			created:     now.Add(-time.Hour),
+		},
+		{
+			name:         "offset is reported without an SLO",
+			annotations:  map[string]string{OffsetAnnotation: "3"},
+			created:      now.Add(-time.Hour),
+			wantReported: true,
+			wantOffset:   3 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := newPod("ns", "pod", nil)
+			pod.Annotations = tt.annotations
+			pod.CreationTimestamp = metav1.NewTime(tt.created)
+
+			c := CheckSLO(tt.slo, pod, now)
+			if c.Reported != tt.wantReported || c.Offset != tt.wantOffset {
+				t.Errorf("got reported=%v offset=%s, want %v %s", c.Reported, c.Offset, tt.wantReported, tt.wantOffset)
+			}
+			if (c.Violation != "") != tt.wantViolated {
+				t.Errorf("got violation %q, want violated=%v", c.Violation, tt.wantViolated)
+			}
+		})
+	}
+}
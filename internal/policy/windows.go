@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"time"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/schedule"
+)
+
+// ChangesAllowed reports whether changes to a policy with the spec take
+// effect at now: it is not suspended and, if it has active windows, one of
+// them is open. It also returns when the next window opens, zero when the
+// policy has no windows or is suspended. A policy with an invalid window is
+// held back.
+func ChangesAllowed(spec *syncv1beta1.TimeSyncPolicySpec, now time.Time) (bool, time.Time, error) {
+	if spec.Suspend {
+		return false, time.Time{}, nil
+	}
+	if len(spec.ActiveWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	open := false
+	var next time.Time
+	for i, w := range spec.ActiveWindows {
+		s, err := schedule.Parse(w.Schedule, w.TimeZone)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("activeWindows[%d]: %w", i, err)
+		}
+		// The window is open if it last opened less than its duration ago.
+		if start := s.Next(now.Add(-w.Duration.Duration)); !start.IsZero() && !start.After(now) {
+			open = true
+		}
+		if start := s.Next(now); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return open, next, nil
+}
+
+// Applied returns the policy as the webhook applies it at now: the policy
+// itself when changes are allowed, and otherwise its last applied spec and
+// generation. It returns false for a held back policy that was never applied.
+func Applied(p *syncv1beta1.TimeSyncPolicy, now time.Time) (*syncv1beta1.TimeSyncPolicy, bool) {
+	if allowed, _, _ := ChangesAllowed(&p.Spec, now); allowed {
+		return p, true
+	}
+	if p.Status.AppliedSpec == nil {
+		return nil, false
+	}
+	applied := p.DeepCopy()
+	applied.Spec = *p.Status.AppliedSpec.DeepCopy()
+	applied.Generation = p.Status.AppliedGeneration
+	return applied, true
+}
+
+// AppliedPolicies returns the policies as the webhook applies them at now,
+// leaving out those that were never applied.
+func AppliedPolicies(policies []syncv1beta1.TimeSyncPolicy, now time.Time) []syncv1beta1.TimeSyncPolicy {
+	out := make([]syncv1beta1.TimeSyncPolicy, 0, len(policies))
+	for i := range policies {
+		if p, ok := Applied(&policies[i], now); ok {
+			out = append(out, *p)
+		}
+	}
+	return out
+}
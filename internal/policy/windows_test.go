@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+)
+
+func TestChangesAllowed(t *testing.T) {
+	saturdayNight := []syncv1beta1.ActiveWindow{{
+		Schedule: "0 22 * * 6",
+		Duration: metav1.Duration{Duration: 4 * time.Hour},
+	}}
+	tests := []struct {
+		name        string
+		spec        syncv1beta1.TimeSyncPolicySpec
+		now         time.Time
+		wantAllowed bool
+		wantNext    time.Time
+		wantErr     bool
+	}{
+		{
+			name:        "no windows",
+			now:         time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC),
+			wantAllowed: true,
+		},
+		{
+			name: "suspended",
+			spec: syncv1beta1.TimeSyncPolicySpec{Suspend: true, ActiveWindows: saturdayNight},
+			now:  time.Date(2025, time.March, 15, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "before the window",
+			spec:     syncv1beta1.TimeSyncPolicySpec{ActiveWindows: saturdayNight},
+			now:      time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2025, time.March, 15, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "inside the window past midnight",
+			spec:        syncv1beta1.TimeSyncPolicySpec{ActiveWindows: saturdayNight},
+			now:         time.Date(2025, time.March, 16, 1, 59, 0, 0, time.UTC),
+			wantAllowed: true,
+			wantNext:    time.Date(2025, time.March, 22, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "when the window closes",
+			spec:     syncv1beta1.TimeSyncPolicySpec{ActiveWindows: saturdayNight},
+			now:      time.Date(2025, time.March, 16, 2, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2025, time.March, 22, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "in the time zone of the window",
+			spec: syncv1beta1.TimeSyncPolicySpec{ActiveWindows: []syncv1beta1.ActiveWindow{{
+				Schedule: "0 22 * * 6",
+				Duration: metav1.Duration{Duration: time.Hour},
+				TimeZone: "Asia/Tokyo",
+			}}},
+			now:         time.Date(2025, time.March, 15, 13, 30, 0, 0, time.UTC),
+			wantAllowed: true,
+			wantNext:    time.Date(2025, time.March, 22, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid window",
+			spec:    syncv1beta1.TimeSyncPolicySpec{ActiveWindows: []syncv1beta1.ActiveWindow{{Schedule: "daily"}}},
+			now:     time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, next, err := ChangesAllowed(&tt.spec, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if allowed != tt.wantAllowed || !next.Equal(tt.wantNext) {
+				t.Errorf("got allowed=%v next=%v, want allowed=%v next=%v", allowed, next, tt.wantAllowed, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestAppliedPolicies(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+	live := newPolicy("live", true, "img:2", nil)
+
+	held := newPolicy("held", true, "img:2", nil)
+	held.Generation = 2
+	held.Spec.Suspend = true
+	held.Status.AppliedSpec = &syncv1beta1.TimeSyncPolicySpec{Enable: true, Template: syncv1beta1.SidecarTemplate{Image: "img:1"}}
+	held.Status.AppliedGeneration = 1
+
+	unapplied := newPolicy("unapplied", true, "img:2", nil)
+	unapplied.Spec.Suspend = true
+
+	got := AppliedPolicies([]syncv1beta1.TimeSyncPolicy{live, held, unapplied}, now)
+	if len(got) != 2 {
+		t.Fatalf("got %d policies, want 2: %+v", len(got), got)
+	}
+	if got[0].Spec.Template.Image != "img:2" {
+		t.Errorf("live policy: got image %q, want img:2", got[0].Spec.Template.Image)
+	}
+	if got[1].Spec.Template.Image != "img:1" || got[1].Generation != 1 {
+		t.Errorf("held policy: got image %q generation %d, want img:1 generation 1",
+			got[1].Spec.Template.Image, got[1].Generation)
+	}
+	if held.Spec.Template.Image != "img:2" {
+		t.Errorf("input policy was modified")
+	}
+}
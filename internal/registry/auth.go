@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Login is a username and password for a registry.
+type Login struct {
+	Username string
+	Password string
+}
+
+// authorization returns the Basic Authorization header value of the login,
+// or "" for anonymous access.
+func (l Login) authorization() string {
+	if l == (Login{}) {
+		return ""
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(l.Username+":"+l.Password))
+}
+
+// Credentials are registry logins keyed by registry host, as in image
+// references: docker.io, ghcr.io, localhost:5000.
+type Credentials map[string]Login
+
+// dockerConfigEntry is a registry entry of a docker config file.
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// ParseDockerConfig reads the logins of the .dockerconfigjson key of a
+// kubernetes.io/dockerconfigjson Secret or the .dockercfg key of a
+// kubernetes.io/dockercfg Secret.
+func ParseDockerConfig(data []byte) (Credentials, error) {
+	var config struct {
+		Auths map[string]dockerConfigEntry `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("decoding docker config: %w", err)
+	}
+	entries := config.Auths
+	if entries == nil {
+		// The legacy .dockercfg format has the entries at the top level.
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("decoding docker config: %w", err)
+		}
+	}
+
+	creds := Credentials{}
+	for server, entry := range entries {
+		login := Login{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("decoding auth of %s: %w", server, err)
+			}
+			user, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("auth of %s is not username:password", server)
+			}
+			login = Login{Username: user, Password: password}
+		}
+		creds[registryHost(server)] = login
+	}
+	return creds, nil
+}
+
+// Merge adds the logins of other for the registries c has none for.
+func (c Credentials) Merge(other Credentials) {
+	for host, login := range other {
+		if _, ok := c[host]; !ok {
+			c[host] = login
+		}
+	}
+}
+
+// registryHost reduces a docker config server, which may be a URL such as
+// https://index.docker.io/v1/, to the registry host of image references.
+func registryHost(server string) string {
+	host := server
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", dockerHubAPI:
+		return dockerHub
+	}
+	return host
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	dockerHub    = "docker.io"
+	dockerHubAPI = "registry-1.docker.io"
+	defaultTag   = "latest"
+)
+
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Reference is a parsed container image reference.
+type Reference struct {
+	// Registry is the registry host, such as docker.io or ghcr.io:443.
+	Registry string
+	// Repository is the repository path within the registry.
+	Repository string
+	// Tag is the tag, "latest" when the reference has neither tag nor digest.
+	Tag string
+	// Digest is set when the reference is pinned.
+	Digest string
+}
+
+// ParseReference parses an image reference of the form
+// [registry/]repository[:tag][@digest], with the same defaults as docker.
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+	name, digest, pinned := strings.Cut(image, "@")
+	if pinned {
+		if !digestPattern.MatchString(digest) {
+			return ref, fmt.Errorf("invalid digest %q in image %q", digest, image)
+		}
+		ref.Digest = digest
+	}
+
+	if first, rest, ok := strings.Cut(name, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, name = first, rest
+	} else {
+		ref.Registry = dockerHub
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if name == "" || name != strings.ToLower(name) {
+		return ref, fmt.Errorf("invalid repository in image %q", image)
+	}
+	if ref.Registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+	return ref, nil
+}
+
+// Identifier returns the digest of a pinned reference, or its tag.
+func (r Reference) Identifier() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// host returns the host serving the registry API.
+func (r Reference) host() string {
+	if r.Registry == dockerHub {
+		return dockerHubAPI
+	}
+	return r.Registry
+}
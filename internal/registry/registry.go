@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry resolves container image tags to digests and verifies
+// cosign signatures, speaking the OCI distribution API directly. Registries
+// are accessed anonymously unless credentials are given for them, which are
+// sent as Basic authentication or exchanged for a bearer token.
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// manifestMediaTypes are the manifest formats accepted when resolving tags.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// maxManifestSize bounds the manifests and signature payloads read.
+const maxManifestSize = 4 << 20
+
+// Client talks to container registries.
+type Client struct {
+	// HTTPClient is used for all requests; http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// Resolve returns the digest the image's tag currently points to, logging in
+// with the credentials of its registry if creds has any. Pinned references
+// are returned as is, without contacting the registry.
+func (c *Client) Resolve(ctx context.Context, image string, creds Credentials) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	resp, err := c.get(ctx, ref, creds, http.MethodHead, "manifests/"+ref.Tag, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if digest := resp.Header.Get("Docker-Content-Digest"); digestPattern.MatchString(digest) {
+		return digest, nil
+	}
+
+	// Not every registry returns the digest header; hash the manifest instead.
+	body, err := c.fetch(ctx, ref, creds, "manifests/"+ref.Tag, manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// fetch returns the body of a registry API resource.
+func (c *Client) fetch(ctx context.Context, ref Reference, creds Credentials, path string, accept []string) ([]byte, error) {
+	resp, err := c.get(ctx, ref, creds, http.MethodGet, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxManifestSize {
+		return nil, fmt.Errorf("%s/%s: response exceeds %d bytes", ref.Repository, path, maxManifestSize)
+	}
+	return body, nil
+}
+
+// get performs a request against the repository. If the registry asks for
+// authentication, it logs in with the credentials of the registry, or
+// fetches an anonymous bearer token without any.
+func (c *Client) get(ctx context.Context, ref Reference, creds Credentials, method, path string, accept []string) (*http.Response, error) {
+	target := fmt.Sprintf("https://%s/v2/%s/%s", ref.host(), ref.Repository, path)
+	resp, err := c.do(ctx, method, target, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := c.authorize(ctx, challenge, creds[ref.Registry])
+		if err != nil {
+			return nil, fmt.Errorf("authenticating to %s: %w", ref.Registry, err)
+		}
+		if resp, err = c.do(ctx, method, target, accept, authorization); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &StatusError{URL: target, StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, method, target string, accept []string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// authorize returns the Authorization header value answering a challenge:
+// the login itself for Basic, or a token for Bearer.
+func (c *Client) authorize(ctx context.Context, challenge string, login Login) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch {
+	case strings.EqualFold(scheme, "Basic") && login != (Login{}):
+		return login.authorization(), nil
+	case strings.EqualFold(scheme, "Bearer"):
+		token, err := c.token(ctx, params, login)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	}
+	return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+}
+
+// token requests a token for the parameters of a Bearer challenge, logging in
+// to the token service with login unless it is empty.
+func (c *Client) token(ctx context.Context, params string, login Login) (string, error) {
+	values := parseChallenge(params)
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("invalid realm in challenge %q", "Bearer "+params)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if v, ok := values[key]; ok {
+			query.Set(key, v)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.do(ctx, http.MethodGet, realm.String(), nil, login.authorization())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &StatusError{URL: realm.String(), StatusCode: resp.StatusCode}
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// parseChallenge parses the comma-separated key="value" pairs of a
+// WWW-Authenticate header.
+func parseChallenge(params string) map[string]string {
+	values := map[string]string{}
+	for params != "" {
+		var pair string
+		if i := strings.Index(params, `",`); i >= 0 {
+			pair, params = params[:i+1], params[i+2:]
+		} else {
+			pair, params = params, ""
+		}
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		values[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+	return values
+}
+
+// StatusError is returned when the registry answers with an unexpected status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
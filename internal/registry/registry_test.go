@@ -0,0 +1,307 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeRegistry is an in-process stand-in for an OCI distribution registry.
+type fakeRegistry struct {
+	manifests map[string][]byte // "repo:tag" or "repo@digest"
+	blobs     map[string][]byte // digest
+	// token, when set, is required as a bearer token obtained from /token.
+	token string
+	// login, when set, is required to obtain the token, or as Basic
+	// authentication without a token.
+	login      Login
+	omitDigest bool
+}
+
+func newFakeRegistry(t *testing.T) (*fakeRegistry, *httptest.Server) {
+	r := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	srv := httptest.NewTLSServer(r)
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// push stores a manifest under a tag and returns its digest.
+func (r *fakeRegistry) push(repo, tag string, manifest []byte) string {
+	digest := digestOf(manifest)
+	r.manifests[repo+":"+tag] = manifest
+	r.manifests[repo+"@"+digest] = manifest
+	return digest
+}
+
+// sign stores a cosign signature of digest made with key.
+func (r *fakeRegistry) sign(t *testing.T, repo, digest string, key *ecdsa.PrivateKey) {
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + repo +
+		`"},"image":{"docker-manifest-digest":"` + digest +
+		`"},"type":"cosign container image signature"},"optional":null}`)
+	sum := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	layerDigest := digestOf(payload)
+	r.blobs[layerDigest] = payload
+
+	m, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     ociManifestMediaType,
+		"layers": []map[string]any{{
+			"mediaType":   SimpleSigningMediaType,
+			"digest":      layerDigest,
+			"size":        len(payload),
+			"annotations": map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.push(repo, SignatureTag(digest), m)
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if r.login != (Login{}) && req.Header.Get("Authorization") != r.login.authorization() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": r.token})
+		return
+	}
+	if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+		w.Header().Set("WWW-Authenticate",
+			`Bearer realm="https://`+req.Host+`/token",service="fake",scope="repository:pull"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.token == "" && r.login != (Login{}) && req.Header.Get("Authorization") != r.login.authorization() {
+		w.Header().Set("WWW-Authenticate", `Basic realm="fake"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	var body []byte
+	if i := strings.LastIndex(path, "/manifests/"); i >= 0 {
+		repo, ref := path[:i], path[i+len("/manifests/"):]
+		sep := ":"
+		if strings.HasPrefix(ref, "sha256:") {
+			sep = "@"
+		}
+		body = r.manifests[repo+sep+ref]
+		if body != nil && !r.omitDigest {
+			w.Header().Set("Docker-Content-Digest", digestOf(body))
+		}
+	} else if i := strings.LastIndex(path, "/blobs/"); i >= 0 {
+		body = r.blobs[path[i+len("/blobs/"):]]
+	}
+	if body == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		image   string
+		want    Reference
+		wantErr bool
+	}{
+		{image: "busybox", want: Reference{Registry: "docker.io", Repository: "library/busybox", Tag: "latest"}},
+		{image: "org/app:1.0", want: Reference{Registry: "docker.io", Repository: "org/app", Tag: "1.0"}},
+		{image: "localhost:5000/app", want: Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{image: "ghcr.io/org/app:v1@" + digest,
+			want: Reference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1", Digest: digest}},
+		{image: "ghcr.io/org/app@sha256:short", wantErr: true},
+		{image: "ghcr.io/Org/app", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.image)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReference(%q) error = %v, wantErr %v", tt.image, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, want %+v", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	reg, srv := newFakeRegistry(t)
+	host := strings.TrimPrefix(srv.URL, "https://")
+	digest := reg.push("org/timesync", "v1", []byte(`{"schemaVersion":2}`))
+	c := &Client{HTTPClient: srv.Client()}
+	login := Login{Username: "puller", Password: "hunter2"}
+
+	tests := []struct {
+		name    string
+		setup   func()
+		image   string
+		creds   Credentials
+		wantErr bool
+	}{
+		{name: "digest header", image: host + "/org/timesync:v1"},
+		{name: "hashes the manifest without a digest header", setup: func() { reg.omitDigest = true },
+			image: host + "/org/timesync:v1"},
+		{name: "anonymous bearer token", setup: func() { reg.token = "secret" }, image: host + "/org/timesync:v1"},
+		{name: "bearer token for a login", setup: func() { reg.token, reg.login = "secret", login },
+			image: host + "/org/timesync:v1", creds: Credentials{host: login}},
+		{name: "basic authentication", setup: func() { reg.login = login },
+			image: host + "/org/timesync:v1", creds: Credentials{host: login}},
+		{name: "private registry without a login", setup: func() { reg.token, reg.login = "secret", login },
+			image: host + "/org/timesync:v1", wantErr: true},
+		{name: "login for another registry", setup: func() { reg.login = login },
+			image: host + "/org/timesync:v1", creds: Credentials{"ghcr.io": login}, wantErr: true},
+		{name: "pinned reference is returned as is", image: "unreachable.invalid/org/timesync@" + digest},
+		{name: "unknown tag", image: host + "/org/timesync:v2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg.omitDigest, reg.token, reg.login = false, "", Login{}
+			if tt.setup != nil {
+				tt.setup()
+			}
+			got, err := c.Resolve(context.Background(), tt.image, tt.creds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != digest {
+				t.Errorf("Resolve() = %q, want %q", got, digest)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	reg, srv := newFakeRegistry(t)
+	host := strings.TrimPrefix(srv.URL, "https://")
+	c := &Client{HTTPClient: srv.Client()}
+
+	signer, signerPEM := newKey(t)
+	_, otherPEM := newKey(t)
+	signed := reg.push("org/signed", "v1", []byte(`{"schemaVersion":2,"signed":true}`))
+	reg.sign(t, "org/signed", signed, signer)
+	unsigned := reg.push("org/unsigned", "v1", []byte(`{"schemaVersion":2}`))
+	// A signature copied from another image must not verify.
+	reg.manifests["org/unsigned:"+SignatureTag(unsigned)] = reg.manifests["org/signed:"+SignatureTag(signed)]
+
+	tests := []struct {
+		name    string
+		image   string
+		digest  string
+		key     []byte
+		wantErr bool
+	}{
+		{name: "valid signature", image: host + "/org/signed:v1", digest: signed, key: signerPEM},
+		{name: "wrong key", image: host + "/org/signed:v1", digest: signed, key: otherPEM, wantErr: true},
+		{name: "signature of another image", image: host + "/org/unsigned:v1", digest: unsigned, key: signerPEM,
+			wantErr: true},
+		{name: "not signed", image: host + "/org/signed:v1", digest: unsigned, key: signerPEM, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParsePublicKey(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.Verify(context.Background(), tt.image, tt.digest, key, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrNoValidSignature) {
+				t.Errorf("Verify() error = %v, want ErrNoValidSignature", err)
+			}
+		})
+	}
+}
+
+func TestParseDockerConfig(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cr:et"))
+	tests := []struct {
+		name    string
+		config  string
+		want    Credentials
+		wantErr bool
+	}{
+		{name: "dockerconfigjson", config: `{"auths":{"ghcr.io":{"username":"me","password":"pw"}}}`,
+			want: Credentials{"ghcr.io": {Username: "me", Password: "pw"}}},
+		{name: "auth field and docker hub URL", config: `{"auths":{"https://index.docker.io/v1/":{"auth":"` + auth + `"}}}`,
+			want: Credentials{"docker.io": {Username: "robot", Password: "s3cr:et"}}},
+		{name: "legacy dockercfg", config: `{"registry.example.com:5000":{"auth":"` + auth + `"}}`,
+			want: Credentials{"registry.example.com:5000": {Username: "robot", Password: "s3cr:et"}}},
+		{name: "auth without a password", config: `{"auths":{"ghcr.io":{"auth":"` +
+			base64.StdEncoding.EncodeToString([]byte("robot")) + `"}}}`, wantErr: true},
+		{name: "not JSON", config: `auths`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDockerConfig([]byte(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDockerConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParseDockerConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyRejectsGarbage(t *testing.T) {
+	if _, err := ParsePublicKey([]byte("not a key")); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// SignatureAnnotation holds the base64 signature of a cosign layer.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// SimpleSigningMediaType is the media type of cosign signature payloads.
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	ociManifestMediaType   = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// ErrNoValidSignature is returned when none of the signatures stored for an
+// image verify against the key.
+var ErrNoValidSignature = errors.New("no valid signature")
+
+// ParsePublicKey parses a PEM-encoded PKIX public key.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// SignatureTag returns the tag under which cosign stores the signatures of
+// the manifest with the given digest.
+func SignatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+// manifest is the subset of an OCI image manifest needed to find signatures.
+type manifest struct {
+	Layers []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// simpleSigning is the subset of a cosign payload that binds it to an image.
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// Verify checks that the image manifest with the given digest carries a
+// cosign signature that verifies against key, logging in with the credentials
+// of its registry if creds has any.
+func (c *Client) Verify(ctx context.Context, image, digest string, key crypto.PublicKey, creds Credentials) error {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return err
+	}
+	if !digestPattern.MatchString(digest) {
+		return fmt.Errorf("invalid digest %q", digest)
+	}
+
+	body, err := c.fetch(ctx, ref, creds, "manifests/"+SignatureTag(digest), []string{ociManifestMediaType})
+	if err != nil {
+		var status *StatusError
+		if errors.As(err, &status) && status.StatusCode == 404 {
+			return fmt.Errorf("%s@%s: %w: image is not signed", ref.Repository, digest, ErrNoValidSignature)
+		}
+		return fmt.Errorf("fetching signatures: %w", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return fmt.Errorf("decoding signature manifest: %w", err)
+	}
+
+	var errs []error
+	for _, layer := range m.Layers {
+		if layer.MediaType != SimpleSigningMediaType {
+			continue
+		}
+		if err := c.verifyLayer(ctx, ref, creds, digest, layer.Digest, layer.Annotations[SignatureAnnotation], key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("%s@%s: %w: %w", ref.Repository, digest, ErrNoValidSignature, errors.Join(errs...))
+}
+
+// verifyLayer checks a single signature layer: the payload must match its
+// digest, refer to the signed image and verify against the key.
+func (c *Client) verifyLayer(ctx context.Context, ref Reference, creds Credentials, digest, layerDigest, signature string, key crypto.PublicKey) error {
+	if !digestPattern.MatchString(layerDigest) {
+		return fmt.Errorf("invalid layer digest %q", layerDigest)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("layer %s: missing or malformed signature", layerDigest)
+	}
+	payload, err := c.fetch(ctx, ref, creds, "blobs/"+layerDigest, nil)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(payload)
+	if "sha256:"+hex.EncodeToString(sum[:]) != layerDigest {
+		return fmt.Errorf("layer %s: payload does not match its digest", layerDigest)
+	}
+
+	var p simpleSigning
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("layer %s: decoding payload: %w", layerDigest, err)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("layer %s: payload signs %q", layerDigest, p.Critical.Image.DockerManifestDigest)
+	}
+	if !verifySignature(key, payload, sum[:], sig) {
+		return fmt.Errorf("layer %s: signature does not verify", layerDigest)
+	}
+	return nil
+}
+
+func verifySignature(key crypto.PublicKey, payload, sum, sig []byte) bool {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, sum, sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, sum, sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, sig)
+	default:
+		return false
+	}
+}
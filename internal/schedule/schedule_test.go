@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	from := time.Date(2025, time.March, 14, 10, 30, 15, 0, time.UTC) // a Friday
+	tests := []struct {
+		expr, timeZone string
+		want           time.Time
+	}{
+		{"* * * * *", "", time.Date(2025, time.March, 14, 10, 31, 0, 0, time.UTC)},
+		{"0 * * * *", "", time.Date(2025, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"*/15 9-17 * * *", "", time.Date(2025, time.March, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 22 * * 6", "", time.Date(2025, time.March, 15, 22, 0, 0, 0, time.UTC)},
+		{"0 2 * * 0", "", time.Date(2025, time.March, 16, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 7", "", time.Date(2025, time.March, 16, 2, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", "", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", "", time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", "", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted.
+		{"0 0 20 * 1", "", time.Date(2025, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * *", "Asia/Tokyo", time.Date(2025, time.March, 15, 3, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", "", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr+" "+tt.timeZone, func(t *testing.T) {
+			s, err := Parse(tt.expr, tt.timeZone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tt := range []struct{ expr, timeZone string }{
+		{"* * * *", ""},
+		{"60 * * * *", ""},
+		{"* 5-3 * * *", ""},
+		{"*/0 * * * *", ""},
+		{"* * 0 * *", ""},
+		{"MON * * * *", ""},
+		{"* * * * *", "Mars/Olympus_Mons"},
+	} {
+		if _, err := Parse(tt.expr, tt.timeZone); err == nil {
+			t.Errorf("Parse(%q, %q) succeeded", tt.expr, tt.timeZone)
+		}
+	}
+}
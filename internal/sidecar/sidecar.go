@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sidecar builds the timesync container from a resolved policy
+// configuration. Each backend contributes its own command and arguments.
+package sidecar
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// NonRootUID is the user the sidecar runs as unless it needs capabilities
+// that only take effect for root.
+const NonRootUID int64 = 65532
+
+// AgentHealthPort is the port the agent backend serves /healthz on. It
+// answers 200 while the clock is synchronized.
+const AgentHealthPort = 8081
+
+// AgentMetricsPort is the port the agent backend serves /metrics on.
+const AgentMetricsPort int32 = 9123
+
+// MetricsPortName names the container port that serves /metrics.
+const MetricsPortName = "metrics"
+
+// InjectedByLabel is set on injected pods to the name of the policy whose
+// sidecar they carry, so that its PodMonitor can select them.
+const InjectedByLabel = "sync.example.com/injected-by"
+
+// RevisionLabel is set on injected pods to the hash of the policy revision
+// whose sidecar they carry.
+const RevisionLabel = "sync.example.com/policy-revision"
+
+// ReadinessGate is the pod condition that gates readiness on clock
+// synchronization when the policy asks for it.
+const ReadinessGate corev1.PodConditionType = "sync.example.com/ClockSynchronized"
+
+// chronyDirs are the directories chronyd writes its pidfile, command socket
+// and drift file to. They are emptyDirs, as the root filesystem is read-only.
+var chronyDirs = []struct{ volume, path string }{
+	{volume: "timesync-chrony-run", path: "/run/chrony"},
+	{volume: "timesync-chrony-state", path: "/var/lib/chrony"},
+}
+
+// chronySynchronized succeeds while chronyd reports a normal leap status,
+// which it only does once it is synchronized to a source.
+var chronySynchronized = []string{"sh", "-c", "chronyc -n tracking | grep -Eq '^Leap status +: Normal'"}
+
+// Container returns the timesync container for cfg.
+func Container(cfg *policy.Config) corev1.Container {
+	c := corev1.Container{
+		Name:      policy.SidecarName,
+		Image:     cfg.Template.Image,
+		Env:       append([]corev1.EnvVar(nil), cfg.Template.Env...),
+		Resources: *cfg.Template.Resources.DeepCopy(),
+
+		SecurityContext: securityContext(cfg.Template.Capabilities),
+	}
+
+	switch cfg.Backend {
+	case syncv1beta1.BackendChrony:
+		// -d keeps chronyd in the foreground and logs to stderr.
+		c.Command = []string{"chronyd"}
+		c.Args = []string{"-d"}
+		if ptr.Deref(c.SecurityContext.RunAsNonRoot, false) {
+			// -U lets chronyd start without root.
+			c.Args = append(c.Args, "-U")
+		}
+		if !cfg.AdjustsClock() {
+			// -x leaves the clock alone and only tracks the offset.
+			c.Args = append(c.Args, "-x")
+		}
+		c.Args = append(c.Args, cfg.Template.Args...)
+		if cfg.ClockAdjustment == syncv1beta1.ClockAdjustmentStep {
+			// Directives follow the options; step whenever the offset
+			// exceeds one second instead of slewing it away.
+			c.Args = append(c.Args, "makestep 1 -1")
+		}
+		for _, dir := range chronyDirs {
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: dir.volume, MountPath: dir.path})
+		}
+	case syncv1beta1.BackendAgent:
+		c.Args = append([]string{"--clock-adjustment=" + agentClockAdjustment(cfg)}, cfg.Template.Args...)
+		c.Ports = []corev1.ContainerPort{{Name: "health", ContainerPort: AgentHealthPort, Protocol: corev1.ProtocolTCP}}
+	default:
+		c.Args = append([]string(nil), cfg.Template.Args...)
+		if len(c.Args) == 0 {
+			// Keep the container running, as v1alpha1 policies always did.
+			c.Args = []string{"sleep", "infinity"}
+		}
+	}
+
+	if port := MetricsPort(cfg.Backend, &cfg.Template); port != 0 {
+		c.Ports = append(c.Ports, corev1.ContainerPort{Name: MetricsPortName, ContainerPort: port, Protocol: corev1.ProtocolTCP})
+	}
+
+	c.ReadinessProbe, c.LivenessProbe = probes(cfg.Backend)
+	if cfg.Template.ReadinessProbe != nil {
+		c.ReadinessProbe = cfg.Template.ReadinessProbe.DeepCopy()
+	}
+	if cfg.Template.LivenessProbe != nil {
+		c.LivenessProbe = cfg.Template.LivenessProbe.DeepCopy()
+	}
+	return c
+}
+
+// MetricsPort returns the port the sidecar serves /metrics on, or zero when
+// it exposes no metrics.
+func MetricsPort(backend syncv1beta1.Backend, template *syncv1beta1.SidecarTemplate) int32 {
+	if template.MetricsPort != 0 {
+		return template.MetricsPort
+	}
+	if backend == syncv1beta1.BackendAgent {
+		return AgentMetricsPort
+	}
+	return 0
+}
+
+// probes returns the readiness and liveness probes of the backend. Both run
+// the same synchronization check: readiness fails after half a minute, while
+// liveness only restarts the sidecar after ten minutes without a sync. The
+// generic backend runs an arbitrary image and has no probes.
+func probes(backend syncv1beta1.Backend) (readiness, liveness *corev1.Probe) {
+	var handler corev1.ProbeHandler
+	switch backend {
+	case syncv1beta1.BackendChrony:
+		handler.Exec = &corev1.ExecAction{Command: chronySynchronized}
+	case syncv1beta1.BackendAgent:
+		handler.HTTPGet = &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("health")}
+	default:
+		return nil, nil
+	}
+	readiness = &corev1.Probe{ProbeHandler: handler, PeriodSeconds: 10, FailureThreshold: 3}
+	liveness = &corev1.Probe{ProbeHandler: *handler.DeepCopy(), InitialDelaySeconds: 60, PeriodSeconds: 60, FailureThreshold: 10}
+	return readiness, liveness
+}
+
+// Inject appends the timesync container described by cfg to spec, along with
+// the volumes it mounts, the image pull secrets it needs that the pod does
+// not already reference and the clock synchronization readiness gate if the
+// policy asks for it.
+func Inject(spec *corev1.PodSpec, cfg *policy.Config) {
+	c := Container(cfg)
+	spec.Containers = append(spec.Containers, c)
+	for _, mount := range c.VolumeMounts {
+		if !slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == mount.Name }) {
+			spec.Volumes = append(spec.Volumes, corev1.Volume{
+				Name:         mount.Name,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			})
+		}
+	}
+	gate := corev1.PodReadinessGate{ConditionType: ReadinessGate}
+	if cfg.ReadinessGate && !slices.Contains(spec.ReadinessGates, gate) {
+		spec.ReadinessGates = append(spec.ReadinessGates, gate)
+	}
+	for _, name := range cfg.ImagePullSecrets {
+		if !slices.Contains(spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name}) {
+			spec.ImagePullSecrets = append(spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+}
+
+// Remove takes the timesync container out of spec, along with the volumes
+// and readiness gate Inject adds for it, and reports whether spec had one.
+// Image pull secrets stay, since other containers may pull with them.
+func Remove(spec *corev1.PodSpec) bool {
+	i := slices.IndexFunc(spec.Containers, func(c corev1.Container) bool { return c.Name == policy.SidecarName })
+	if i < 0 {
+		return false
+	}
+	spec.Containers = slices.Delete(spec.Containers, i, i+1)
+	spec.Volumes = slices.DeleteFunc(spec.Volumes, func(v corev1.Volume) bool {
+		return slices.ContainsFunc(chronyDirs, func(d struct{ volume, path string }) bool { return d.volume == v.Name })
+	})
+	spec.ReadinessGates = slices.DeleteFunc(spec.ReadinessGates, func(g corev1.PodReadinessGate) bool {
+		return g.ConditionType == ReadinessGate
+	})
+	return true
+}
+
+// agentClockAdjustment returns the agent's --clock-adjustment value.
+func agentClockAdjustment(cfg *policy.Config) string {
+	if !cfg.AdjustsClock() {
+		return "none"
+	}
+	return strings.ToLower(string(cfg.ClockAdjustment))
+}
+
+// securityContext returns a security context that satisfies the restricted
+// Pod Security Standard, unless caps includes capabilities it forbids. Those
+// are only effective for root, so the sidecar then runs as root instead.
+func securityContext(caps []corev1.Capability) *corev1.SecurityContext {
+	sc := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+			Add:  append([]corev1.Capability(nil), caps...),
+		},
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	if len(policy.ForbiddenCapabilities(policy.LevelRestricted, caps)) > 0 {
+		sc.RunAsUser = ptr.To(int64(0))
+	} else {
+		sc.RunAsNonRoot = ptr.To(true)
+		sc.RunAsUser = ptr.To(NonRootUID)
+	}
+	return sc
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+func TestContainer(t *testing.T) {
+	tests := []struct {
+		name        string
+		backend     syncv1beta1.Backend
+		adjustment  syncv1beta1.ClockAdjustment
+		args        []string
+		wantCommand []string
+		wantArgs    []string
+	}{
+		{name: "generic without args keeps the legacy placeholder", wantArgs: []string{"sleep", "infinity"}},
+		{name: "generic with args", backend: syncv1beta1.BackendGeneric, args: []string{"run"}, wantArgs: []string{"run"}},
+		{name: "chrony", backend: syncv1beta1.BackendChrony, args: []string{"-f", "/etc/chrony.conf"},
+			wantCommand: []string{"chronyd"}, wantArgs: []string{"-d", "-U", "-x", "-f", "/etc/chrony.conf"}},
+		{name: "chrony slew", backend: syncv1beta1.BackendChrony, adjustment: syncv1beta1.ClockAdjustmentSlew,
+			wantCommand: []string{"chronyd"}, wantArgs: []string{"-d", "-U"}},
+		{name: "chrony step", backend: syncv1beta1.BackendChrony, adjustment: syncv1beta1.ClockAdjustmentStep,
+			args: []string{"-f", "/etc/chrony.conf"}, wantCommand: []string{"chronyd"},
+			wantArgs: []string{"-d", "-U", "-f", "/etc/chrony.conf", "makestep 1 -1"}},
+		{name: "agent", backend: syncv1beta1.BackendAgent, wantArgs: []string{"--clock-adjustment=none"}},
+		{name: "agent step", backend: syncv1beta1.BackendAgent, adjustment: syncv1beta1.ClockAdjustmentStep,
+			args: []string{"-v"}, wantArgs: []string{"--clock-adjustment=step", "-v"}},
+		{name: "generic ignores the adjustment", adjustment: syncv1beta1.ClockAdjustmentSlew, args: []string{"run"},
+			wantArgs: []string{"run"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Container(&policy.Config{
+				Backend:         tt.backend,
+				ClockAdjustment: tt.adjustment,
+				Template:        syncv1beta1.SidecarTemplate{Image: "img:1", Args: tt.args},
+			})
+			if c.Name != policy.SidecarName || c.Image != "img:1" {
+				t.Fatalf("unexpected container identity %q %q", c.Name, c.Image)
+			}
+			if !reflect.DeepEqual(c.Command, tt.wantCommand) {
+				t.Errorf("command: got %v, want %v", c.Command, tt.wantCommand)
+			}
+			if !reflect.DeepEqual(c.Args, tt.wantArgs) {
+				t.Errorf("args: got %v, want %v", c.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestContainerProbes(t *testing.T) {
+	custom := &corev1.Probe{ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"check"}}}}
+
+	tests := []struct {
+		name          string
+		backend       syncv1beta1.Backend
+		readiness     *corev1.Probe
+		wantReadiness bool
+		wantExec      bool
+		wantHTTP      bool
+	}{
+		{name: "generic has no probes"},
+		{name: "chrony checks the leap status", backend: syncv1beta1.BackendChrony, wantReadiness: true, wantExec: true},
+		{name: "agent checks healthz", backend: syncv1beta1.BackendAgent, wantReadiness: true, wantHTTP: true},
+		{name: "template probe wins", backend: syncv1beta1.BackendAgent, readiness: custom, wantReadiness: true, wantExec: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Container(&policy.Config{
+				Backend:  tt.backend,
+				Template: syncv1beta1.SidecarTemplate{Image: "img:1", ReadinessProbe: tt.readiness},
+			})
+			if (c.ReadinessProbe != nil) != tt.wantReadiness {
+				t.Fatalf("readiness probe: got %v, want present=%v", c.ReadinessProbe, tt.wantReadiness)
+			}
+			if !tt.wantReadiness {
+				if c.LivenessProbe != nil {
+					t.Errorf("liveness probe: got %v, want none", c.LivenessProbe)
+				}
+				return
+			}
+			if (c.ReadinessProbe.Exec != nil) != tt.wantExec || (c.ReadinessProbe.HTTPGet != nil) != tt.wantHTTP {
+				t.Errorf("readiness probe handler: got %+v", c.ReadinessProbe.ProbeHandler)
+			}
+			if c.LivenessProbe == nil {
+				t.Fatal("missing liveness probe")
+			}
+			outage := func(p *corev1.Probe) int32 { return p.PeriodSeconds * p.FailureThreshold }
+			if tt.readiness == nil && outage(c.LivenessProbe) <= outage(c.ReadinessProbe) {
+				t.Errorf("liveness probe must tolerate longer outages than readiness: got %v", c.LivenessProbe)
+			}
+		})
+	}
+}
+
+func TestContainerMetricsPort(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  syncv1beta1.Backend
+		port     int32
+		wantPort int32
+	}{
+		{name: "generic exposes no metrics"},
+		{name: "chrony exposes no metrics by default", backend: syncv1beta1.BackendChrony},
+		{name: "chrony with a bundled exporter", backend: syncv1beta1.BackendChrony, port: 9123, wantPort: 9123},
+		{name: "agent defaults", backend: syncv1beta1.BackendAgent, wantPort: AgentMetricsPort},
+		{name: "agent with a custom port", backend: syncv1beta1.BackendAgent, port: 8000, wantPort: 8000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Container(&policy.Config{
+				Backend:  tt.backend,
+				Template: syncv1beta1.SidecarTemplate{Image: "img:1", MetricsPort: tt.port},
+			})
+			var got int32
+			for _, p := range c.Ports {
+				if p.Name == MetricsPortName {
+					got = p.ContainerPort
+				}
+			}
+			if got != tt.wantPort {
+				t.Errorf("metrics port: got %d, want %d", got, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestContainerSecurityContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		caps        []corev1.Capability
+		wantNonRoot bool
+	}{
+		{name: "restricted by default", wantNonRoot: true},
+		{name: "restricted capability stays non-root", caps: []corev1.Capability{"NET_BIND_SERVICE"}, wantNonRoot: true},
+		{name: "SYS_TIME runs as root", caps: []corev1.Capability{"SYS_TIME"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := Container(&policy.Config{
+				Template: syncv1beta1.SidecarTemplate{Image: "img:1", Capabilities: tt.caps},
+			}).SecurityContext
+			if sc == nil {
+				t.Fatal("missing security context")
+			}
+			if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+				t.Error("privilege escalation must be disabled")
+			}
+			if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+				t.Error("root filesystem must be read-only")
+			}
+			if sc.SeccompProfile == nil || sc.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+				t.Errorf("seccomp profile: got %v", sc.SeccompProfile)
+			}
+			if !reflect.DeepEqual(sc.Capabilities.Drop, []corev1.Capability{"ALL"}) {
+				t.Errorf("dropped capabilities: got %v", sc.Capabilities.Drop)
+			}
+			if !reflect.DeepEqual(sc.Capabilities.Add, tt.caps) {
+				t.Errorf("added capabilities: got %v, want %v", sc.Capabilities.Add, tt.caps)
+			}
+			nonRoot := sc.RunAsNonRoot != nil && *sc.RunAsNonRoot
+			if nonRoot != tt.wantNonRoot {
+				t.Errorf("runAsNonRoot: got %v, want %v", nonRoot, tt.wantNonRoot)
+			}
+		})
+	}
+}
+
+func TestTemplateSysTimeDoesNotRunAsRoot(t *testing.T) {
+	p := syncv1beta1.TimeSyncPolicy{Spec: syncv1beta1.TimeSyncPolicySpec{
+		Enable:          true,
+		ClockAdjustment: syncv1beta1.ClockAdjustmentNone,
+		Template:        syncv1beta1.SidecarTemplate{Image: "img:1", Capabilities: []corev1.Capability{"SYS_TIME"}},
+	}}
+	p.Name = "p"
+	ns := &corev1.Namespace{}
+	ns.Name = "ns"
+	d := policy.ApplyClockAdjustment(policy.Resolve([]syncv1beta1.TimeSyncPolicy{p}, ns, nil), ns, nil)
+	if !d.Inject() {
+		t.Fatalf("expected a sidecar configuration; trace %v", d.Trace)
+	}
+	sc := Container(d.Config).SecurityContext
+	if slices.Contains(sc.Capabilities.Add, "SYS_TIME") {
+		t.Errorf("added capabilities: got %v, want no SYS_TIME", sc.Capabilities.Add)
+	}
+	if sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot || sc.RunAsUser == nil || *sc.RunAsUser == 0 {
+		t.Errorf("got runAsNonRoot %v and runAsUser %v, want a non-root user", sc.RunAsNonRoot, sc.RunAsUser)
+	}
+}
+
+func TestChronyContainerRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		caps     []corev1.Capability
+		wantArgs []string
+	}{
+		{name: "non-root skips the root check", wantArgs: []string{"-d", "-U", "-x"}},
+		{name: "root with SYS_TIME", caps: []corev1.Capability{"SYS_TIME"}, wantArgs: []string{"-d", "-x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}},
+				Volumes:    []corev1.Volume{{Name: "data"}},
+			}
+			Inject(spec, &policy.Config{
+				Backend:  syncv1beta1.BackendChrony,
+				Template: syncv1beta1.SidecarTemplate{Image: "chrony:4", Capabilities: tt.caps},
+			})
+
+			c := spec.Containers[1]
+			if !reflect.DeepEqual(c.Args, tt.wantArgs) {
+				t.Errorf("args: got %v, want %v", c.Args, tt.wantArgs)
+			}
+			if !*c.SecurityContext.ReadOnlyRootFilesystem {
+				t.Error("root filesystem must be read-only")
+			}
+			wantMounts := []corev1.VolumeMount{
+				{Name: "timesync-chrony-run", MountPath: "/run/chrony"},
+				{Name: "timesync-chrony-state", MountPath: "/var/lib/chrony"},
+			}
+			if !reflect.DeepEqual(c.VolumeMounts, wantMounts) {
+				t.Errorf("volumeMounts: got %v, want %v", c.VolumeMounts, wantMounts)
+			}
+			emptyDir := corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+			wantVolumes := []corev1.Volume{
+				{Name: "data"},
+				{Name: "timesync-chrony-run", VolumeSource: emptyDir},
+				{Name: "timesync-chrony-state", VolumeSource: emptyDir},
+			}
+			if !reflect.DeepEqual(spec.Volumes, wantVolumes) {
+				t.Errorf("volumes: got %v, want %v", spec.Volumes, wantVolumes)
+			}
+		})
+	}
+}
+
+func TestInjectDeduplicatesPullSecrets(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers:       []corev1.Container{{Name: "app"}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "shared"}},
+	}
+	Inject(spec, &policy.Config{
+		Template:         syncv1beta1.SidecarTemplate{Image: "img:1"},
+		ImagePullSecrets: []string{"shared", "timesync-pull"},
+	})
+
+	if len(spec.Containers) != 2 || spec.Containers[1].Name != policy.SidecarName {
+		t.Fatalf("sidecar not appended: %v", spec.Containers)
+	}
+	want := []corev1.LocalObjectReference{{Name: "shared"}, {Name: "timesync-pull"}}
+	if !reflect.DeepEqual(spec.ImagePullSecrets, want) {
+		t.Errorf("imagePullSecrets: got %v, want %v", spec.ImagePullSecrets, want)
+	}
+	if len(spec.ReadinessGates) != 0 {
+		t.Errorf("readinessGates: got %v, want none", spec.ReadinessGates)
+	}
+}
+
+func TestInjectAddsReadinessGateOnce(t *testing.T) {
+	gate := corev1.PodReadinessGate{ConditionType: ReadinessGate}
+	spec := &corev1.PodSpec{ReadinessGates: []corev1.PodReadinessGate{gate}}
+	Inject(spec, &policy.Config{
+		Template:      syncv1beta1.SidecarTemplate{Image: "img:1"},
+		ReadinessGate: true,
+	})
+
+	if !reflect.DeepEqual(spec.ReadinessGates, []corev1.PodReadinessGate{gate}) {
+		t.Errorf("readinessGates: got %v, want %v", spec.ReadinessGates, []corev1.PodReadinessGate{gate})
+	}
+}
+
+func TestRemoveUndoesInject(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "app"}},
+		Volumes:    []corev1.Volume{{Name: "data"}},
+	}
+	want := spec.DeepCopy()
+	Inject(spec, &policy.Config{
+		Backend:       syncv1beta1.BackendChrony,
+		Template:      syncv1beta1.SidecarTemplate{Image: "img:1"},
+		ReadinessGate: true,
+	})
+
+	if !Remove(spec) {
+		t.Fatal("Remove found no sidecar")
+	}
+	// Remove leaves empty slices where Inject found none.
+	if !equality.Semantic.DeepEqual(spec, want) {
+		t.Errorf("got %+v, want %+v", spec, want)
+	}
+	if Remove(spec) {
+		t.Error("Remove found a sidecar twice")
+	}
+}
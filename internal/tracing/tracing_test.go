@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// collector is a stand-in for an OTLP collector that keeps the names and
+// attributes of the spans it receives.
+type collector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	spans map[string]map[string]string
+}
+
+func (c *collector) Export(
+	_ context.Context,
+	req *collectortrace.ExportTraceServiceRequest,
+) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				attrs := map[string]string{}
+				for _, kv := range span.Attributes {
+					attrs[kv.Key] = kv.Value.GetStringValue()
+				}
+				attrs["status"] = span.Status.GetCode().String()
+				c.spans[span.Name] = attrs
+			}
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func startCollector(t *testing.T) (*collector, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collector{spans: map[string]map[string]string{}}
+	srv := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(srv, c)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return c, lis.Addr().String()
+}
+
+func TestSetupExportsSpans(t *testing.T) {
+	c, endpoint := startCollector(t)
+	ctx := context.Background()
+
+	shutdown, err := Setup(ctx, Options{Endpoint: endpoint, Insecure: true, SampleRatio: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := Tracer().Start(ctx, "TimeSyncPolicy.Reconcile")
+	span.SetAttributes(attribute.String("policy", "cluster"))
+	End(span, nil)
+	_, failed := Tracer().Start(ctx, "webhook.getNamespace")
+	End(failed, errors.New("not found"))
+
+	if err := shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if got := c.spans["TimeSyncPolicy.Reconcile"]; got["policy"] != "cluster" {
+		t.Errorf("reconcile span: got %v, want policy=cluster", got)
+	}
+	if got := c.spans["webhook.getNamespace"]; got["status"] != "STATUS_CODE_ERROR" {
+		t.Errorf("failed span: got %v, want an error status", got)
+	}
+}
+
+func TestSetupWithoutEndpointIsDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/metrics"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+	"github.com/Septimus4/timesync-operator/internal/sidecar"
+	"github.com/Septimus4/timesync-operator/internal/tracing"
+)
+
+// recorder emits the Events of Audit mode policies.
+var recorder record.EventRecorder
+
+// requestNamespace returns the namespace of the admitted object, falling back
+// to the namespace of the admission request when the object does not set one.
+func requestNamespace(ctx context.Context, namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		return req.Namespace
+	}
+	return ""
+}
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sync.example.com,resources=namespacetimesyncpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sync.example.com,resources=clockadjustmentallowlists,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sync.example.com,resources=timesyncexceptions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=limitranges;resourcequotas,verbs=get;list;watch
+
+// resolve looks up the namespace, the cluster policies and the tenant
+// overrides and decides whether the pod should receive the sidecar, pinning
+// its image and checking the result against the clock adjustment allowlists,
+// the LimitRanges and ResourceQuotas and the Pod Security level of the
+// namespace. Lookup failures are logged and result in an empty decision so
+// that admission is never blocked by the operator; the error is returned for
+// callers that must not mistake a failed lookup for a pod no policy selects.
+func resolve(ctx context.Context, namespace string, pod *corev1.Pod) (policy.Decision, error) {
+	logger := logf.FromContext(ctx)
+
+	if policy.HasSidecar(pod) {
+		logger.Info("Timesync sidecar already present; skipping")
+		return policy.Decision{}, nil
+	}
+
+	ns := &corev1.Namespace{}
+	nsCtx, span := tracing.Tracer().Start(ctx, "webhook.getNamespace",
+		trace.WithAttributes(attribute.String("namespace", namespace)))
+	err := k8sClient.Get(nsCtx, client.ObjectKey{Name: namespace}, ns)
+	tracing.End(span, err)
+	if err != nil {
+		logger.Error(err, "Failed to get namespace")
+		return policy.Decision{}, err
+	}
+
+	policies := &syncv1beta1.TimeSyncPolicyList{}
+	listCtx, span := tracing.Tracer().Start(ctx, "webhook.listPolicies")
+	err = k8sClient.List(listCtx, policies)
+	span.SetAttributes(attribute.Int("policies", len(policies.Items)))
+	tracing.End(span, err)
+	if err != nil {
+		logger.Error(err, "Failed to list TimeSyncPolicies")
+		return policy.Decision{}, err
+	}
+
+	ctx, span = tracing.Tracer().Start(ctx, "webhook.decide")
+	// Policies held back by suspend or activeWindows apply their last
+	// applied spec.
+	applied := policy.AppliedPolicies(policies.Items, time.Now())
+	// Match conditions may look at who is creating the pod.
+	var user *authenticationv1.UserInfo
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		user = &req.UserInfo
+	}
+	decision := policy.ApplyRollout(policy.ApplySkipRules(policy.ResolveFor(applied, ns, pod, user), pod), pod)
+	if decision.Inject() {
+		exceptions := &syncv1beta1.TimeSyncExceptionList{}
+		if err := k8sClient.List(ctx, exceptions); err != nil {
+			logger.Error(err, "Failed to list TimeSyncExceptions; not exempting the pod")
+		}
+		decision = policy.ApplyExceptions(decision, exceptions.Items, ns, pod, time.Now())
+	}
+	if decision.Inject() {
+		overrides := &syncv1alpha1.NamespaceTimeSyncPolicyList{}
+		if err := k8sClient.List(ctx, overrides, client.InNamespace(namespace)); err != nil {
+			logger.Error(err, "Failed to list NamespaceTimeSyncPolicies; using cluster policy settings")
+		} else {
+			decision = policy.ApplyOverrides(decision, overrides.Items)
+		}
+		decision = policy.ApplyImagePolicy(decision)
+		allowlists := &syncv1beta1.ClockAdjustmentAllowlistList{}
+		if decision.Inject() && decision.Config.AdjustsClock() {
+			if err := k8sClient.List(ctx, allowlists); err != nil {
+				logger.Error(err, "Failed to list ClockAdjustmentAllowlists; not adjusting the clock")
+			}
+		}
+		decision = policy.ApplyClockAdjustment(decision, ns, allowlists.Items)
+		decision = applyResources(ctx, decision, namespace, pod)
+		decision = policy.ApplyPodSecurity(decision, ns)
+	}
+	for _, step := range decision.Trace {
+		logger.V(1).Info("Policy evaluated", "step", step.String())
+	}
+	span.SetAttributes(
+		attribute.Bool("inject", decision.Inject()),
+		attribute.String("skipped", string(decision.Skipped)),
+		attribute.Int("warnings", len(decision.Warnings)),
+	)
+	if decision.Inject() {
+		span.SetAttributes(attribute.String("policy", decision.Config.PolicyName))
+	}
+	tracing.End(span, decision.Refusal)
+	return decision, nil
+}
+
+// applyResources checks the sidecar resources against the LimitRanges and
+// ResourceQuotas of the namespace.
+func applyResources(ctx context.Context, decision policy.Decision, namespace string, pod *corev1.Pod) policy.Decision {
+	if !decision.Inject() {
+		return decision
+	}
+	limitRanges := &corev1.LimitRangeList{}
+	if err := k8sClient.List(ctx, limitRanges, client.InNamespace(namespace)); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list LimitRanges; using default sidecar resources")
+	}
+	quotas := &corev1.ResourceQuotaList{}
+	if err := k8sClient.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ResourceQuotas; not checking the sidecar against them")
+	}
+	var spec *corev1.PodSpec
+	if pod != nil {
+		spec = &pod.Spec
+	}
+	return policy.ApplyResources(decision, spec, limitRanges.Items, quotas.Items)
+}
+
+// injectSidecar adds the timesync container described by cfg to spec and
+// labels obj with the policy and revision it comes from.
+func injectSidecar(ctx context.Context, obj *metav1.ObjectMeta, spec *corev1.PodSpec, cfg *policy.Config) {
+	_, span := tracing.Tracer().Start(ctx, "webhook.patch", trace.WithAttributes(attribute.String("policy", cfg.PolicyName)))
+	defer span.End()
+
+	sidecar.Inject(spec, cfg)
+	if obj.Labels == nil {
+		obj.Labels = map[string]string{}
+	}
+	obj.Labels[sidecar.InjectedByLabel] = cfg.PolicyName
+	obj.Labels[sidecar.RevisionLabel] = cfg.Revision
+}
+
+// recordSkip annotates an object that a matching policy did not inject
+// because of a skip rule, counting it the first time it is seen, or because a
+// TimeSyncException exempts it.
+func recordSkip(ctx context.Context, obj metav1.Object, decision policy.Decision) {
+	if decision.Exception != "" {
+		logf.FromContext(ctx).Info("Exempting from timesync sidecar injection", "exception", decision.Exception)
+		setAnnotation(obj, policy.ExemptedAnnotation, decision.Exception)
+		return
+	}
+	if decision.Skipped == "" {
+		return
+	}
+	logf.FromContext(ctx).Info("Skipping timesync sidecar injection", "reason", decision.Skipped)
+	if obj.GetAnnotations()[policy.SkippedAnnotation] == string(decision.Skipped) {
+		return
+	}
+	setAnnotation(obj, policy.SkippedAnnotation, string(decision.Skipped))
+	metrics.SkippedPods.WithLabelValues(string(decision.Skipped)).Inc()
+}
+
+// recordAudit annotates an object that an Audit mode policy would have
+// injected, leaving it otherwise unchanged, and emits an Event on the policy
+// describing what enforcing it would have done.
+func recordAudit(ctx context.Context, obj metav1.Object, object string, decision policy.Decision) {
+	message := policy.AuditMessage(decision, object)
+	logf.FromContext(ctx).Info("Auditing timesync sidecar injection", "policy", decision.Config.PolicyName, "result", message)
+	setAnnotation(obj, policy.AuditAnnotation, decision.Config.PolicyName)
+
+	// A dry-run request must not leave Events behind.
+	if req, err := admission.RequestFromContext(ctx); recorder == nil || (err == nil && ptr.Deref(req.DryRun, false)) {
+		return
+	}
+	tsp := &syncv1beta1.TimeSyncPolicy{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: decision.Config.PolicyName}, tsp); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to get TimeSyncPolicy; not recording the audit Event")
+		return
+	}
+	if decision.Refusal != nil {
+		recorder.Event(tsp, corev1.EventTypeWarning, "WouldRefuse", message)
+		return
+	}
+	recorder.Event(tsp, corev1.EventTypeNormal, "WouldInject", message)
+}
+
+// recordWarnings logs the warnings raised while injecting the sidecar, lists
+// them in an annotation on the mutated object and returns them to the client
+// as admission warnings.
+func recordWarnings(ctx context.Context, obj metav1.Object, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	if collected, ok := ctx.Value(warningsKey{}).(*[]string); ok {
+		*collected = append(*collected, warnings...)
+	}
+	for _, w := range warnings {
+		logf.FromContext(ctx).Info("Timesync sidecar injected with warning", "warning", w)
+	}
+	setAnnotation(obj, policy.WarningsAnnotation, strings.Join(warnings, "; "))
+}
+
+// setAnnotation sets the annotation key of obj to value.
+func setAnnotation(obj metav1.Object, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
+// warningsKey holds the warnings recorded while handling an admission
+// request in its context.
+type warningsKey struct{}
+
+// registerDefaulter serves defaulter for obj at the path the webhook builder
+// would use. Unlike the builder, it passes the warnings the defaulter records
+// back in the admission response and traces each admission.
+func registerDefaulter(mgr ctrl.Manager, obj runtime.Object, defaulter admission.CustomDefaulter) error {
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	wh := admission.WithCustomDefaulter(mgr.GetScheme(), obj, defaulter)
+	wh.Handler = withWarnings(wh.Handler)
+	path := "/mutate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+	// The API server sends its trace context along with the request.
+	mgr.GetWebhookServer().Register(path, otelhttp.NewHandler(wh, "admission "+gvk.Kind))
+	return nil
+}
+
+// registerValidator serves validator for obj at the path the webhook builder
+// would use, tracing each admission like registerDefaulter.
+func registerValidator(mgr ctrl.Manager, obj runtime.Object, validator admission.CustomValidator) error {
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	wh := admission.WithCustomValidator(mgr.GetScheme(), obj, validator)
+	path := "/validate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+	mgr.GetWebhookServer().Register(path, otelhttp.NewHandler(wh, "validation "+gvk.Kind))
+	return nil
+}
+
+// withWarnings collects the warnings recorded by h into its response.
+func withWarnings(h admission.Handler) admission.Handler {
+	return admission.HandlerFunc(func(ctx context.Context, req admission.Request) admission.Response {
+		var warnings []string
+		resp := h.Handle(context.WithValue(ctx, warningsKey{}, &warnings), req)
+		resp.Warnings = append(resp.Warnings, warnings...)
+		return resp
+	})
+}
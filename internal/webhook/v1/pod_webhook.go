@@ -18,25 +18,38 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	"slices"
+	"strings"
+
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/controller"
+	"github.com/Septimus4/timesync-operator/internal/policy"
 )
 
 // SetupPodWebhookWithManager registers the webhook for Pod in the manager.
-func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+// revisionNamespace is where the controller stores the ControllerRevisions of
+// policies.
+func SetupPodWebhookWithManager(mgr ctrl.Manager, revisionNamespace string) error {
 	k8sClient = mgr.GetClient()
-	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).
-		WithDefaulter(&PodCustomDefaulter{}).
-		Complete()
+	apiReader = mgr.GetAPIReader()
+	policyRevisionNamespace = revisionNamespace
+	recorder = mgr.GetEventRecorderFor("timesync-webhook")
+	if err := registerDefaulter(mgr, &corev1.Pod{}, &PodCustomDefaulter{}); err != nil {
+		return err
+	}
+	return registerValidator(mgr, &corev1.Pod{}, &PodCustomValidator{})
 }
 
 // +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=mpod-v1.kb.io,admissionReviewVersions=v1
@@ -52,6 +65,13 @@ type PodCustomDefaulter struct {
 var _ webhook.CustomDefaulter = &PodCustomDefaulter{}
 var k8sClient client.Client
 
+// apiReader reads the ControllerRevisions of policies, which the webhook looks
+// up too rarely to keep an informer for, in policyRevisionNamespace.
+var (
+	apiReader               client.Reader
+	policyRevisionNamespace string
+)
+
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Pod.
 func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	pod, ok := obj.(*corev1.Pod)
@@ -62,44 +82,138 @@ func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) er
 	logger := logf.FromContext(ctx)
 	logger.Info("Webhook triggered for Pod", "name", pod.GetName(), "namespace", pod.GetNamespace())
 
-	for _, c := range pod.Spec.Containers {
-		if c.Name == "timesync" {
-			logger.Info("Timesync sidecar already present; skipping")
-			return nil
-		}
+	namespace := requestNamespace(ctx, pod.Namespace)
+	// A failed lookup injects nothing rather than blocking the pod.
+	decision, _ := resolve(ctx, namespace, pod)
+	recordSkip(ctx, &pod.ObjectMeta, decision)
+	if !decision.Inject() {
+		return nil
 	}
-
-	ns := &corev1.Namespace{}
-	if err := k8sClient.Get(ctx, client.ObjectKey{Name: pod.Namespace}, ns); err != nil {
-		logger.Error(err, "Failed to get namespace")
+	if !decision.Config.InjectsPods() {
+		logger.V(1).Info("Policy only injects workload templates; skipping Pod", "policy", decision.Config.PolicyName)
 		return nil
 	}
-
-	policies := &syncv1alpha1.TimeSyncPolicyList{}
-	if err := k8sClient.List(ctx, policies); err != nil {
-		logger.Error(err, "Failed to list TimeSyncPolicies")
+	if decision.Config.Audits() {
+		recordAudit(ctx, &pod.ObjectMeta, "pod "+podName(pod, namespace), decision)
 		return nil
 	}
+	if decision.Refusal != nil {
+		return decision.Refusal
+	}
 
-	for _, policy := range policies.Items {
-		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
-		if err != nil {
-			continue
-		}
+	logger.Info("Injecting timesync sidecar from policy", "policy", decision.Config.PolicyName)
+	injectSidecar(ctx, &pod.ObjectMeta, &pod.Spec, decision.Config)
+	recordWarnings(ctx, &pod.ObjectMeta, decision.Warnings)
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=vpod-v1.kb.io,admissionReviewVersions=v1
+
+// PodCustomValidator rejects pods that lack the sidecar of a policy with
+// enforcement Require once every mutating webhook has run.
+type PodCustomValidator struct{}
+
+var _ webhook.CustomValidator = &PodCustomValidator{}
+
+// ValidateCreate decides which policy the pod falls under as if it had no
+// sidecar yet, and checks that the pod carries the sidecar that policy would
+// inject.
+func (v *PodCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod object but got %T", obj)
+	}
 
-		if selector.Matches(labels.Set(ns.Labels)) && policy.Spec.Enable {
-			logger.Info("Injecting timesync sidecar from policy", "policy", policy.Name)
+	namespace := requestNamespace(ctx, pod.Namespace)
+	bare := pod.DeepCopy()
+	bare.Spec.Containers = slices.DeleteFunc(bare.Spec.Containers, func(c corev1.Container) bool {
+		return c.Name == policy.SidecarName
+	})
+	decision, _ := resolve(ctx, namespace, bare)
+	if !decision.Inject() || !decision.Config.Requires() || decision.Refusal != nil {
+		return nil, nil
+	}
 
-			sidecar := corev1.Container{
-				Name:  "timesync",
-				Image: policy.Spec.Image,
-				Args:  []string{"sleep", "infinity"},
-			}
+	name := podName(pod, namespace)
+	if !controller.HasTimeSyncSidecar(pod) {
+		return nil, fmt.Errorf("pod %s must run the %s sidecar required by TimeSyncPolicy %q",
+			name, policy.SidecarName, decision.Config.PolicyName)
+	}
+	want := decision.Config.Template.Image
+	i := slices.IndexFunc(pod.Spec.Containers, func(c corev1.Container) bool { return c.Name == policy.SidecarName })
+	got := pod.Spec.Containers[i].Image
+	if got == want {
+		return nil, nil
+	}
+	// Workload templates keep the sidecar they were injected with until they
+	// are edited, so images of earlier revisions are only warned about.
+	stale, err := staleImage(ctx, decision.Config, got)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list policy revisions; admitting the pod", "policy", decision.Config.PolicyName)
+		return nil, nil
+	}
+	if stale {
+		return admission.Warnings{fmt.Sprintf("pod %s runs %s sidecar image %q from an earlier revision of TimeSyncPolicy %q, which now injects %q; update the workload to pick it up",
+			name, policy.SidecarName, got, decision.Config.PolicyName, want)}, nil
+	}
+	return nil, fmt.Errorf("pod %s runs %s sidecar image %q, but TimeSyncPolicy %q requires %q",
+		name, policy.SidecarName, got, decision.Config.PolicyName, want)
+}
 
-			pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
-			break
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list
+
+// staleImage reports whether image is one the policy of cfg injected before:
+// the image of one of its stored revisions, pinned or not, or its current
+// image pinned to a digest it no longer resolves to. The controller stores no
+// revisions without a revision namespace.
+func staleImage(ctx context.Context, cfg *policy.Config, image string) (bool, error) {
+	if policy.PinsDigest(cfg.ImagePolicy) {
+		if tag, _, ok := strings.Cut(cfg.Template.Image, "@"); ok && policy.PinnedFrom(image, tag) {
+			return true, nil
 		}
 	}
+	if policyRevisionNamespace == "" {
+		return false, nil
+	}
+	reader := apiReader
+	if reader == nil {
+		reader = k8sClient
+	}
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := reader.List(ctx, revisions, client.InNamespace(policyRevisionNamespace),
+		client.MatchingLabels{controller.PolicyLabel: cfg.PolicyName}); err != nil {
+		return false, err
+	}
+	for _, rev := range revisions.Items {
+		var spec syncv1beta1.TimeSyncPolicySpec
+		if err := json.Unmarshal(rev.Data.Raw, &spec); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to decode policy revision", "revision", rev.Name)
+			continue
+		}
+		if image == spec.Template.Image || policy.PinnedFrom(image, spec.Template.Image) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
-	return nil
+// ValidateUpdate admits every update; the containers of a pod cannot change
+// after it is created.
+func (v *PodCustomValidator) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete admits every deletion.
+func (v *PodCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// podName names a pod being admitted as namespace/name, using its generateName
+// when the API server has not named it yet.
+func podName(pod *corev1.Pod, namespace string) string {
+	name := pod.Name
+	if name == "" {
+		name = pod.GenerateName + "*"
+	}
+	return namespace + "/" + name
 }
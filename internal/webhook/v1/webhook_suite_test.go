@@ -19,6 +19,7 @@ package v1
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
@@ -30,8 +31,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	syncv1alpha1 "github.com/Septimus4/timesync-operator/api/v1alpha1"
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	webhooksyncv1beta1 "github.com/Septimus4/timesync-operator/internal/webhook/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -73,6 +79,9 @@ var _ = BeforeSuite(func() {
 	err = syncv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = syncv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	// +kubebuilder:scaffold:scheme
 
 	By("bootstrapping test environment")
@@ -113,7 +122,13 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupPodWebhookWithManager(mgr)
+	err = SetupPodWebhookWithManager(mgr, "default")
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupWorkloadWebhooksWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = webhooksyncv1beta1.SetupTimeSyncPolicyWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook
@@ -221,6 +236,8 @@ var _ = Describe("Pod Webhook", func() {
 			}
 		}
 		Expect(sidecarFound).To(BeTrue())
+		Expect(updatedPod.Labels).To(HaveKeyWithValue("sync.example.com/injected-by", "test-policy"))
+		Expect(updatedPod.Labels).To(HaveKeyWithValue("sync.example.com/policy-revision", Not(BeEmpty())))
 	})
 
 	It("should not inject the sidecar if no policy matches the namespace", func() {
@@ -255,4 +272,802 @@ var _ = Describe("Pod Webhook", func() {
 			Expect(c.Name).NotTo(Equal("timesync"))
 		}
 	})
+
+	It("should inject the sidecar into workload templates when the policy targets workloads", func() {
+		By("Creating a namespace with matching labels")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "workload-namespace",
+				Labels: map[string]string{"env": "workloads"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		By("Creating a TimeSyncPolicy that targets workloads only")
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "workload-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "workloads"},
+				},
+				Enable:          true,
+				Template:        syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				InjectionTarget: syncv1beta1.InjectionTargetWorkloads,
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a Deployment in the matching namespace")
+		labels := map[string]string{"app": "web"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "workload-namespace",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "app", Image: "app:latest"},
+						},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+		defer k8sClient.Delete(ctx, deployment)
+
+		By("Verifying the timesync sidecar is part of the pod template")
+		updated := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployment), updated)).To(Succeed())
+		Expect(updated.Spec.Template.Spec.Containers).To(ContainElement(
+			HaveField("Name", "timesync"),
+		))
+
+		By("Re-injecting the template with the current image when the Deployment is edited")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(policy), policy)).To(Succeed())
+		policy.Spec.Template.Image = "timesync:v2"
+		Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+		sidecarImages := func() []string {
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployment), updated)).To(Succeed())
+			updated.Spec.Template.Spec.Containers[0].Image = "app:" + fmt.Sprint(time.Now().UnixNano())
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+			var images []string
+			for _, c := range updated.Spec.Template.Spec.Containers {
+				if c.Name == "timesync" {
+					images = append(images, c.Image)
+				}
+			}
+			return images
+		}
+		Eventually(sidecarImages).Should(Equal([]string{"timesync:v2"}))
+
+		By("Removing the sidecar from the template once the policy is disabled")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(policy), policy)).To(Succeed())
+		policy.Spec.Enable = false
+		Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+		Eventually(sidecarImages).Should(BeEmpty())
+		Expect(updated.Spec.Template.Labels).NotTo(HaveKey("sync.example.com/injected-by"))
+
+		By("Verifying bare Pods are left alone")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bare-pod",
+				Namespace: "workload-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), result)).To(Succeed())
+		Expect(result.Spec.Containers).NotTo(ContainElement(HaveField("Name", "timesync")))
+	})
+
+	It("should apply tenant overrides allowed by the cluster policy", func() {
+		By("Creating a namespace with matching labels")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "tenant-namespace",
+				Labels: map[string]string{"env": "tenant"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		By("Creating a TimeSyncPolicy that lets tenants choose the image")
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "tenant"},
+				},
+				Enable:           true,
+				Template:         syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				AllowedOverrides: []syncv1beta1.OverridableField{syncv1beta1.OverridableFieldImage},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a NamespaceTimeSyncPolicy in the tenant namespace")
+		override := &syncv1alpha1.NamespaceTimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tenant-override",
+				Namespace: "tenant-namespace",
+			},
+			Spec: syncv1alpha1.NamespaceTimeSyncPolicySpec{
+				Image: "timesync:tenant",
+			},
+		}
+		Expect(k8sClient.Create(ctx, override)).To(Succeed())
+		defer k8sClient.Delete(ctx, override)
+
+		By("Creating a Pod in the tenant namespace")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tenant-pod",
+				Namespace: "tenant-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		By("Verifying the sidecar uses the tenant image")
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), result)).To(Succeed())
+		Expect(result.Spec.Containers).To(ContainElement(And(
+			HaveField("Name", "timesync"),
+			HaveField("Image", "timesync:tenant"),
+		)))
+	})
+
+	It("should drop capabilities the namespace's Pod Security level forbids", func() {
+		By("Creating a namespace that enforces the baseline level")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "baseline-namespace",
+				Labels: map[string]string{
+					"env":                                "baseline",
+					"pod-security.kubernetes.io/enforce": "baseline",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		By("Creating a TimeSyncPolicy whose sidecar needs SYS_TIME")
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "sys-time-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "baseline"},
+				},
+				Enable: true,
+				Template: syncv1beta1.SidecarTemplate{
+					Image:        "timesync:latest",
+					Capabilities: []corev1.Capability{"SYS_TIME"},
+				},
+				PodSecurityAction: syncv1beta1.PodSecurityActionDowngrade,
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a Pod in the namespace")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "baseline-pod",
+				Namespace: "baseline-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		By("Verifying the sidecar was injected without SYS_TIME and with a warning")
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), result)).To(Succeed())
+		Expect(result.Spec.Containers).To(ContainElement(And(
+			HaveField("Name", "timesync"),
+			HaveField("SecurityContext.Capabilities.Add", BeEmpty()),
+			HaveField("SecurityContext.RunAsNonRoot", HaveValue(BeTrue())),
+		)))
+		Expect(result.Annotations).To(HaveKeyWithValue("sync.example.com/warnings", ContainSubstring("SYS_TIME")))
+
+		By("Refusing the Pod instead when the policy asks for it")
+		policy.Spec.PodSecurityAction = syncv1beta1.PodSecurityActionRefuse
+		Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+		refused := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "refused-pod",
+				Namespace: "baseline-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, refused)).To(MatchError(ContainSubstring("forbids capabilities")))
+	})
+
+	It("should fit default sidecar resources to the namespace's LimitRange", func() {
+		By("Creating a namespace with a container minimum")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "limited-namespace",
+				Labels: map[string]string{"env": "limited"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		limitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "limits",
+				Namespace: "limited-namespace",
+			},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{{
+					Type: corev1.LimitTypeContainer,
+					Min:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20m")},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, limitRange)).To(Succeed())
+		defer k8sClient.Delete(ctx, limitRange)
+
+		By("Creating a TimeSyncPolicy without sidecar resources")
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "limited-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "limited"},
+				},
+				Enable:   true,
+				Template: syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a Pod in the namespace")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "limited-pod",
+				Namespace: "limited-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		By("Verifying the sidecar requests the LimitRange minimum")
+		quantity := func(q resource.Quantity) string { return q.String() }
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), result)).To(Succeed())
+		Expect(result.Spec.Containers).To(ContainElement(And(
+			HaveField("Name", "timesync"),
+			HaveField("Resources.Requests", HaveKeyWithValue(corev1.ResourceCPU, WithTransform(quantity, Equal("20m")))),
+			HaveField("Resources.Limits", HaveKeyWithValue(corev1.ResourceMemory, WithTransform(quantity, Equal("32Mi")))),
+		)))
+	})
+
+	It("should skip host network pods and record why", func() {
+		By("Creating a namespace and a TimeSyncPolicy with the default skip rules")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "skip-namespace",
+				Labels: map[string]string{"env": "skip"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "skip-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "skip"},
+				},
+				Enable:   true,
+				Template: syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a host network Pod in the namespace")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "host-network-pod",
+				Namespace: "skip-namespace",
+			},
+			Spec: corev1.PodSpec{
+				HostNetwork: true,
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		By("Verifying the sidecar was not injected and the reason was recorded")
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), result)).To(Succeed())
+		Expect(result.Spec.Containers).NotTo(ContainElement(HaveField("Name", "timesync")))
+		Expect(result.Annotations).To(HaveKeyWithValue("sync.example.com/skipped", "HostNetwork"))
+	})
+
+	It("should record a skipped DaemonSet without touching its pod template", func() {
+		By("Creating a namespace and a TimeSyncPolicy that skips DaemonSets")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "skip-daemonset-namespace",
+				Labels: map[string]string{"env": "skip-daemonset"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "skip-daemonset-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "skip-daemonset"},
+				},
+				Enable:          true,
+				Template:        syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				InjectionTarget: syncv1beta1.InjectionTargetPodsAndWorkloads,
+				Skip:            []syncv1beta1.SkipReason{syncv1beta1.SkipReasonDaemonSet},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a DaemonSet in the namespace")
+		labels := map[string]string{"app": "agent"}
+		daemonSet := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "agent",
+				Namespace: "skip-daemonset-namespace",
+			},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "agent", Image: "agent:latest"},
+						},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, daemonSet)).To(Succeed())
+		defer k8sClient.Delete(ctx, daemonSet)
+
+		By("Verifying the skip is recorded on the DaemonSet rather than its template")
+		result := &appsv1.DaemonSet{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(daemonSet), result)).To(Succeed())
+		Expect(result.Annotations).To(HaveKeyWithValue("sync.example.com/skipped", "DaemonSet"))
+		Expect(result.Spec.Template.Annotations).To(BeEmpty())
+		Expect(result.Spec.Template.Spec.Containers).NotTo(ContainElement(HaveField("Name", "timesync")))
+	})
+
+	It("should only annotate pods and emit an Event in audit mode", func() {
+		By("Creating a namespace and a TimeSyncPolicy in audit mode")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "audit-namespace",
+				Labels: map[string]string{"env": "audit"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "audit-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "audit"},
+				},
+				Enable:          true,
+				Mode:            syncv1beta1.PolicyModeAudit,
+				Template:        syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				InjectionTarget: syncv1beta1.InjectionTargetPodsAndWorkloads,
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a Pod in the namespace")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "audited-pod",
+				Namespace: "audit-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		By("Verifying the sidecar was not injected and the audit was recorded")
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), result)).To(Succeed())
+		Expect(result.Spec.Containers).To(HaveLen(1))
+		Expect(result.Labels).NotTo(HaveKey("sync.example.com/injected-by"))
+		Expect(result.Annotations).To(HaveKeyWithValue("sync.example.com/would-inject", "audit-policy"))
+
+		By("Verifying an Event describes the injection")
+		Eventually(func(g Gomega) {
+			events := &corev1.EventList{}
+			g.Expect(k8sClient.List(ctx, events, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+			g.Expect(events.Items).To(ContainElement(And(
+				HaveField("InvolvedObject.Name", "audit-policy"),
+				HaveField("Reason", "WouldInject"),
+				HaveField("Message", ContainSubstring("into pod audit-namespace/audited-pod")),
+			)))
+		}).Should(Succeed())
+
+		By("Creating a Deployment in the namespace")
+		labels := map[string]string{"app": "audited"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "audited-deployment",
+				Namespace: "audit-namespace",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "app", Image: "app:latest"},
+						},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+		defer k8sClient.Delete(ctx, deployment)
+
+		By("Verifying the audit is recorded on the Deployment, leaving its pod template alone")
+		updated := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployment), updated)).To(Succeed())
+		Expect(updated.Annotations).To(HaveKeyWithValue("sync.example.com/would-inject", "audit-policy"))
+		Expect(updated.Spec.Template.Annotations).To(BeEmpty())
+		Expect(updated.Spec.Template.Spec.Containers).To(HaveLen(1))
+	})
+
+	It("should not inject pods outside the rollout", func() {
+		By("Creating a namespace and a TimeSyncPolicy rolled out to no pods")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "rollout-namespace",
+				Labels: map[string]string{"env": "rollout"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rollout-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "rollout"},
+				},
+				Enable:   true,
+				Template: syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				Rollout:  &syncv1beta1.Rollout{Percentage: 0},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a Pod in the namespace")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "canary-pod",
+				Namespace: "rollout-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		By("Verifying the sidecar was not injected")
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), result)).To(Succeed())
+		Expect(result.Spec.Containers).NotTo(ContainElement(HaveField("Name", "timesync")))
+	})
+
+	It("should not inject pods exempted by a TimeSyncException", func() {
+		By("Creating a namespace, a TimeSyncPolicy and an exception for ledger pods")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "exception-namespace",
+				Labels: map[string]string{"env": "exception"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "exception-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "exception"},
+				},
+				Enable:   true,
+				Template: syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		exception := &syncv1beta1.TimeSyncException{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ledger-exception",
+			},
+			Spec: syncv1beta1.TimeSyncExceptionSpec{
+				Namespaces:  []string{"exception-namespace"},
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ledger"}},
+				Reason:      "ledger pins its own NTP client",
+				Owner:       "billing-team",
+				ExpiresAt:   metav1.NewTime(time.Now().Add(time.Hour)),
+			},
+		}
+		Expect(k8sClient.Create(ctx, exception)).To(Succeed())
+		defer k8sClient.Delete(ctx, exception)
+
+		By("Creating an exempted and a regular Pod in the namespace")
+		exempted := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ledger-pod",
+				Namespace: "exception-namespace",
+				Labels:    map[string]string{"app": "ledger"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, exempted)).To(Succeed())
+		defer k8sClient.Delete(ctx, exempted)
+
+		regular := exempted.DeepCopy()
+		regular.Name = "web-pod"
+		regular.Labels = map[string]string{"app": "web"}
+		Expect(k8sClient.Create(ctx, regular)).To(Succeed())
+		defer k8sClient.Delete(ctx, regular)
+
+		By("Verifying only the regular Pod was injected")
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(exempted), result)).To(Succeed())
+		Expect(result.Spec.Containers).NotTo(ContainElement(HaveField("Name", "timesync")))
+		Expect(result.Annotations).To(HaveKeyWithValue("sync.example.com/exempted-by", "ledger-exception"))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(regular), result)).To(Succeed())
+		Expect(result.Spec.Containers).To(ContainElement(HaveField("Name", "timesync")))
+	})
+
+	It("should only inject pods that satisfy the match conditions", func() {
+		By("Creating a namespace and a TimeSyncPolicy skipping pods with their own NTP daemon")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cel-namespace",
+				Labels: map[string]string{"env": "cel"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cel-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "cel"},
+				},
+				Enable:   true,
+				Template: syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+				MatchConditions: []syncv1beta1.MatchCondition{{
+					Name:       "no-ntpd",
+					Expression: "!object.spec.containers.exists(c, c.name == 'ntpd')",
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a Pod with and a Pod without an NTP daemon")
+		withNTP := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ntpd-pod",
+				Namespace: "cel-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+					{Name: "ntpd", Image: "ntpd:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, withNTP)).To(Succeed())
+		defer k8sClient.Delete(ctx, withNTP)
+
+		withoutNTP := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-pod",
+				Namespace: "cel-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, withoutNTP)).To(Succeed())
+		defer k8sClient.Delete(ctx, withoutNTP)
+
+		By("Verifying only the Pod without an NTP daemon was injected")
+		result := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(withNTP), result)).To(Succeed())
+		Expect(result.Spec.Containers).NotTo(ContainElement(HaveField("Name", "timesync")))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(withoutNTP), result)).To(Succeed())
+		Expect(result.Spec.Containers).To(ContainElement(HaveField("Name", "timesync")))
+	})
+
+	It("should reject pods without the required sidecar image", func() {
+		By("Creating a namespace and a TimeSyncPolicy that requires its sidecar")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "require-namespace",
+				Labels: map[string]string{"env": "require"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		defer k8sClient.Delete(ctx, namespace)
+
+		policy := &syncv1beta1.TimeSyncPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "require-policy",
+			},
+			Spec: syncv1beta1.TimeSyncPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "require"},
+				},
+				Enable:      true,
+				Enforcement: syncv1beta1.EnforcementRequire,
+				Template:    syncv1beta1.SidecarTemplate{Image: "timesync:latest"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+		defer k8sClient.Delete(ctx, policy)
+
+		By("Creating a Pod that brings its own timesync container")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "impostor-pod",
+				Namespace: "require-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+					{Name: "timesync", Image: "timesync:old"},
+				},
+			},
+		}
+		err := k8sClient.Create(ctx, pod)
+		Expect(err).To(MatchError(And(
+			ContainSubstring(`runs timesync sidecar image "timesync:old"`),
+			ContainSubstring(`TimeSyncPolicy "require-policy" requires "timesync:latest"`),
+		)))
+
+		By("Admitting a Pod that runs the image of an earlier revision")
+		previous := policy.Spec.DeepCopy()
+		previous.Template.Image = "timesync:old"
+		data, err := json.Marshal(previous)
+		Expect(err).NotTo(HaveOccurred())
+		revision := &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "require-policy-old",
+				Namespace: "default",
+				Labels:    map[string]string{"sync.example.com/policy": "require-policy"},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: 1,
+		}
+		Expect(k8sClient.Create(ctx, revision)).To(Succeed())
+		defer k8sClient.Delete(ctx, revision)
+		pod.Name = "stale-pod"
+		Eventually(func() error { return k8sClient.Create(ctx, pod) }).Should(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+
+		By("Rejecting a Pod whose sidecar was removed after injection")
+		bare := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bare-pod",
+				Namespace: "require-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		_, err = (&PodCustomValidator{}).ValidateCreate(ctx, bare)
+		Expect(err).To(MatchError(
+			`pod require-namespace/bare-pod must run the timesync sidecar required by TimeSyncPolicy "require-policy"`,
+		))
+
+		By("Admitting a Pod the webhook injects")
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "compliant-pod",
+				Namespace: "require-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app:latest"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		defer k8sClient.Delete(ctx, pod)
+	})
 })
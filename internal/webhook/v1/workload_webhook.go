@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/Septimus4/timesync-operator/internal/sidecar"
+)
+
+// SetupWorkloadWebhooksWithManager registers the webhooks that inject the
+// sidecar into the pod templates of Deployments, StatefulSets, DaemonSets,
+// Jobs and CronJobs.
+func SetupWorkloadWebhooksWithManager(mgr ctrl.Manager) error {
+	k8sClient = mgr.GetClient()
+	recorder = mgr.GetEventRecorderFor("timesync-webhook")
+	for _, obj := range []runtime.Object{
+		&appsv1.Deployment{},
+		&appsv1.StatefulSet{},
+		&appsv1.DaemonSet{},
+		&batchv1.Job{},
+		&batchv1.CronJob{},
+	} {
+		if err := registerDefaulter(mgr, obj, &WorkloadCustomDefaulter{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The workload webhooks fail open: while the operator is down, workloads are
+// still admitted and pod admission still applies the policies. They skip the
+// operator's own namespace and kube-system (see
+// config/webhook/mutating_namespace_selector_patch.yaml) as the pod webhooks do.
+// +kubebuilder:webhook:path=/mutate-apps-v1-deployment,mutating=true,failurePolicy=ignore,sideEffects=None,groups=apps,resources=deployments,verbs=create;update,versions=v1,name=mdeployment-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-apps-v1-statefulset,mutating=true,failurePolicy=ignore,sideEffects=None,groups=apps,resources=statefulsets,verbs=create;update,versions=v1,name=mstatefulset-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-apps-v1-daemonset,mutating=true,failurePolicy=ignore,sideEffects=None,groups=apps,resources=daemonsets,verbs=create;update,versions=v1,name=mdaemonset-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-batch-v1-job,mutating=true,failurePolicy=ignore,sideEffects=None,groups=batch,resources=jobs,verbs=create,versions=v1,name=mjob-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-batch-v1-cronjob,mutating=true,failurePolicy=ignore,sideEffects=None,groups=batch,resources=cronjobs,verbs=create;update,versions=v1,name=mcronjob-v1.kb.io,admissionReviewVersions=v1
+
+// WorkloadCustomDefaulter injects the timesync sidecar into workload pod
+// templates for policies whose injectionTarget includes workloads. It shares
+// the policy resolution and container construction with PodCustomDefaulter.
+//
+// Jobs are only mutated on create because their pod template is immutable.
+type WorkloadCustomDefaulter struct {
+}
+
+var _ webhook.CustomDefaulter = &WorkloadCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter for the supported workload kinds.
+func (d *WorkloadCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	workload, ok := obj.(client.Object)
+	if !ok {
+		return fmt.Errorf("expected a workload object but got %T", obj)
+	}
+	template, err := podTemplate(obj)
+	if err != nil {
+		return err
+	}
+
+	logger := logf.FromContext(ctx)
+	logger.Info("Webhook triggered for workload", "kind", fmt.Sprintf("%T", obj),
+		"name", workload.GetName(), "namespace", workload.GetNamespace())
+
+	namespace := requestNamespace(ctx, workload.GetNamespace())
+	// A template the webhook injected before is decided again without its
+	// sidecar, so that an edited workload picks up the current image and
+	// settings of its policy, or loses the sidecar the policy no longer
+	// gives it. A timesync container the user added is left alone.
+	bare := template.DeepCopy()
+	injected := bare.Labels[sidecar.InjectedByLabel] != "" && sidecar.Remove(&bare.Spec)
+	if injected {
+		delete(bare.Labels, sidecar.InjectedByLabel)
+		delete(bare.Labels, sidecar.RevisionLabel)
+	}
+	pod := &corev1.Pod{ObjectMeta: *bare.ObjectMeta.DeepCopy(), Spec: *bare.Spec.DeepCopy()}
+	pod.Namespace = namespace
+	// The template describes pods the workload will own, which decides
+	// the DaemonSet skip rule and the rollout the template falls in.
+	gvk, err := apiutil.GVKForObject(obj, k8sClient.Scheme())
+	if err != nil {
+		return err
+	}
+	pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(workload, gvk)}
+
+	decision, err := resolve(ctx, namespace, pod)
+	if err != nil {
+		// Keep the template as it is rather than strip its sidecar.
+		return nil
+	}
+	// Skips, audits and warnings are recorded on the workload itself:
+	// annotating the template would change its hash and restart the
+	// workload's pods.
+	recordSkip(ctx, workload, decision)
+	if !decision.Inject() || !decision.Config.InjectsWorkloads() {
+		*template = *bare
+		return nil
+	}
+	if decision.Config.Audits() {
+		*template = *bare
+		recordAudit(ctx, workload, gvk.Kind+" "+namespace+"/"+workload.GetName(), decision)
+		return nil
+	}
+	if decision.Refusal != nil {
+		return decision.Refusal
+	}
+
+	logger.Info("Injecting timesync sidecar into pod template from policy", "policy", decision.Config.PolicyName)
+	injectSidecar(ctx, &bare.ObjectMeta, &bare.Spec, decision.Config)
+	*template = *bare
+	recordWarnings(ctx, workload, decision.Warnings)
+	return nil
+}
+
+// podTemplate returns the pod template embedded in a supported workload.
+func podTemplate(obj runtime.Object) (*corev1.PodTemplateSpec, error) {
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template, nil
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template, nil
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template, nil
+	case *batchv1.Job:
+		return &w.Spec.Template, nil
+	case *batchv1.CronJob:
+		return &w.Spec.JobTemplate.Spec.Template, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload type %T", obj)
+	}
+}
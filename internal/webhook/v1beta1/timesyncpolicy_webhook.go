@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	syncv1beta1 "github.com/Septimus4/timesync-operator/api/v1beta1"
+	"github.com/Septimus4/timesync-operator/internal/policy"
+)
+
+// SetupTimeSyncPolicyWebhookWithManager registers the conversion and
+// validating webhooks for TimeSyncPolicy in the manager. v1beta1 is the hub;
+// v1alpha1 converts to and from it.
+func SetupTimeSyncPolicyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&syncv1beta1.TimeSyncPolicy{}).
+		WithValidator(&TimeSyncPolicyCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-sync-example-com-v1beta1-timesyncpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=sync.example.com,resources=timesyncpolicies,verbs=create;update,versions=v1beta1,name=vtimesyncpolicy-v1beta1.kb.io,admissionReviewVersions=v1
+
+// TimeSyncPolicyCustomValidator rejects policies the operator could not
+// compile, such as a match condition that selects a field pods do not have,
+// and templates that ask for CAP_SYS_TIME outside of clockAdjustment.
+type TimeSyncPolicyCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TimeSyncPolicyCustomValidator{}
+
+// ValidateCreate checks the template capabilities and compiles the selectors
+// and match conditions of the policy.
+func (v *TimeSyncPolicyCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tsp, ok := obj.(*syncv1beta1.TimeSyncPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a TimeSyncPolicy object but got %T", obj)
+	}
+	if slices.Contains(tsp.Spec.Template.Capabilities, policy.SysTimeCapability) {
+		return nil, field.Forbidden(field.NewPath("spec", "template", "capabilities"),
+			"SYS_TIME is only granted through clockAdjustment, in namespaces a ClockAdjustmentAllowlist permits")
+	}
+	_, err := policy.Compile(tsp)
+	return nil, err
+}
+
+// ValidateUpdate validates the updated policy like ValidateCreate.
+func (v *TimeSyncPolicyCustomValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+// ValidateDelete admits every deletion.
+func (v *TimeSyncPolicyCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}